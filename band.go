@@ -0,0 +1,147 @@
+package receipt
+
+import (
+	"fmt"
+	"image"
+	"io"
+)
+
+// RenderBands draws the document of the width and passes it to the function in bands of the height (in pixels),
+// so the raster of the whole document is never kept in memory. The blocks of Lines and the rows of the tables
+// are drawn one after another and the bands are passed as soon as they are finished, the memory is bounded by
+// the band and the highest block. The rows joined by RowSpan are one block, the header of the table is drawn
+// once at its top. Any other object (a table nested in Cols or Grid too) is one block which is rasterised whole
+// before its bands are passed. The band is reused after the function returns, the first error stops rendering
+// and is returned
+//
+//	err := RenderBands(zReport, Millimeters(72), 256, func(band *image.RGBA) error {
+//		return printer.PrintRaster(band)
+//	})
+func RenderBands(d DrawStruct, width Measure, height int, f func(band *image.RGBA) error) error {
+	return renderBands(NewRollCanvas(width), d, height, f)
+}
+
+// WriteBands draws the document band by band and writes the dots to w as the packed 1-bit rows of Monochrome.
+// The document is drawn for the monochrome output (see Canvas.SetMonochrome): the text has no grey edges
+// and the options dither the pictures only
+func WriteBands(w io.Writer, d DrawStruct, width Measure, height int, options ...MonochromeOption) error {
+	var (
+		roll      = NewRollCanvas(width)
+		threshold = OptionThreshold(uint8(newMonochromeSettings(options).threshold))
+	)
+	roll.SetMonochrome(options...)
+	return renderBands(roll, d, height, func(band *image.RGBA) error {
+		_, err := w.Write(ToMonochrome(band, threshold).Pix)
+		return err
+	})
+}
+
+func renderBands(roll *RollCanvas, d DrawStruct, height int, f func(band *image.RGBA) error) error {
+	if height <= 0 {
+		return fmt.Errorf("cannot render bands of height %d: the height must be positive", height)
+	}
+	roll.SetChunk(height)
+	roll.StreamBands(height, f)
+	for _, block := range bandBlocks(d) {
+		roll.Write(block)
+		if roll.err != nil {
+			return roll.err
+		}
+	}
+	return roll.Close()
+}
+
+// bandBlocks splits the document into the blocks which are written one after another,
+// the nested Lines and the rows of the tables are split too
+func bandBlocks(d DrawStruct) []DrawStruct {
+	switch v := d.(type) {
+	case lines:
+		var blocks []DrawStruct
+		for _, block := range v.lines {
+			blocks = append(blocks, bandBlocks(block)...)
+		}
+		return blocks
+	case table:
+		return v.bands()
+	default:
+		return []DrawStruct{d}
+	}
+}
+
+type (
+	// tableBand is the run of the rows of the table which is written to the roll as one block
+	tableBand struct {
+		table   table
+		rows    []layoutRow
+		columns *tableBandColumns
+	}
+	// tableBandColumns keeps the columns of the table measured over all its rows, the bands share them
+	tableBandColumns struct {
+		left, width int
+		dpi         float64
+		bounds      []int
+		decimals    []decimalColumn
+	}
+)
+
+// bands splits the rows of the table into the runs which no spanned cell crosses. The header is drawn
+// by the first band only, the columns are as wide as if the table was drawn at once
+func (t table) bands() []DrawStruct {
+	if len(t.columns) == 0 {
+		return []DrawStruct{t}
+	}
+	var (
+		rows     = t.layoutRows()
+		occupied = make([]int, len(t.columns))
+		columns  = &tableBandColumns{}
+		bands    []DrawStruct
+		start    int
+	)
+	for i, row := range rows {
+		if row.finish {
+			for col := range occupied {
+				occupied[col] = 0
+			}
+		}
+		occupyRows(occupied, splitTableRow(occupied, row.getColumnStruct))
+		if spanned(occupied) {
+			continue
+		}
+		bands = append(bands, tableBand{table: t, rows: rows[start : i+1], columns: columns})
+		start = i + 1
+	}
+	if start < len(rows) {
+		bands = append(bands, tableBand{table: t, rows: rows[start:], columns: columns})
+	}
+	return bands
+}
+
+// spanned reports whether the cells of the rows above still span some column
+func spanned(occupied []int) bool {
+	for _, rows := range occupied {
+		if rows > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (b tableBand) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	c := b.columns
+	if c.bounds == nil || c.left != rect.Min.X || c.width != rect.Dx() || c.dpi != canvas.dpi {
+		// the columns are measured once for all the bands
+		*c = tableBandColumns{
+			left:     rect.Min.X,
+			width:    rect.Dx(),
+			dpi:      canvas.dpi,
+			bounds:   b.table.columnBounds(canvas, rect),
+			decimals: b.table.decimalColumns(canvas),
+		}
+	}
+	layout := newTableLayout(b.table, c.bounds)
+	layout.decimals = c.decimals
+	return image.Point{
+		X: rect.Min.X,
+		Y: b.table.writePixelRows(canvas, layout, b.rows, rect.Min.Y),
+	}
+}
@@ -0,0 +1,118 @@
+package receipt
+
+import (
+	"fmt"
+	"image"
+	"testing"
+)
+
+func TestRenderBandsHeight(t *testing.T) {
+	for _, height := range []int{0, -1} {
+		called := false
+		err := RenderBands(Text("line"), Millimeters(72), height, func(band *image.RGBA) error {
+			called = true
+			return nil
+		})
+		if err == nil {
+			t.Errorf("height %d: error expected", height)
+		}
+		if called {
+			t.Errorf("height %d: no band expected", height)
+		}
+	}
+}
+
+func TestRenderBandsLines(t *testing.T) {
+	var (
+		rows  = make([]DrawStruct, 50)
+		bands int
+		total int
+	)
+	for i := range rows {
+		rows[i] = Text("line")
+	}
+	err := RenderBands(Lines(rows...), Millimeters(72), 64, func(band *image.RGBA) error {
+		if band.Rect.Dy() > 64 {
+			t.Errorf("the band is %d pixels high, 64 expected at most", band.Rect.Dy())
+		}
+		bands++
+		total += band.Rect.Dy()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bands < 2 {
+		t.Errorf("%d bands are passed, the document is expected to be split", bands)
+	}
+	roll := NewRollCanvas(Millimeters(72))
+	roll.Write(Lines(rows...))
+	if want := roll.Image().Rect.Dy(); total != want {
+		t.Errorf("the bands are %d pixels high in total, %d expected", total, want)
+	}
+}
+
+// longTable is the table of the rows of the report, every tenth row spans two rows
+func longTable(n int) DrawStruct {
+	var (
+		columns = []TableColumn{Column("Item", 3), Column("Qty", 1), Column("Sum", 2, OptionAlignment(AlignDecimal))}
+		rows    = make([]TableRow, 0, n)
+	)
+	for i := 0; i < n; i++ {
+		if i%10 == 0 && i+1 < n {
+			rows = append(rows,
+				Cols(RowSpan(Text(fmt.Sprintf("item %d", i)), 2), Text("1"), Text(fmt.Sprintf("%d.50", i))),
+				Cols(Text("2"), Text("1.5")),
+			)
+			i++
+			continue
+		}
+		rows = append(rows, Cols(Text(fmt.Sprintf("item %d", i)), Text("1"), Text(fmt.Sprintf("%d.0", i))))
+	}
+	return Table(columns, rows...).WithOptions(
+		OptionFooter(Cols(ColSpan(Text("Total"), 2), FooterSum())),
+	)
+}
+
+func TestBandBlocksTable(t *testing.T) {
+	blocks := bandBlocks(longTable(50))
+	// the header, the rows with the rows spanned by RowSpan joined, the footer
+	if want := 1 + 50 - 5 + 1; len(blocks) != want {
+		t.Errorf("the table is split into %d blocks, %d expected", len(blocks), want)
+	}
+	if len(blocks[0].(tableBand).rows) != 1 || len(blocks[1].(tableBand).rows) != 2 {
+		t.Errorf("the first blocks are %d and %d rows, the header and the spanned rows are expected",
+			len(blocks[0].(tableBand).rows), len(blocks[1].(tableBand).rows))
+	}
+}
+
+func TestRenderBandsTable(t *testing.T) {
+	const height = 64
+	var (
+		tbl  = longTable(200)
+		roll = NewRollCanvas(Millimeters(72))
+		img  = image.NewRGBA(image.Rect(0, 0, roll.width, 0))
+		kept int
+	)
+	err := renderBands(roll, tbl, height, func(band *image.RGBA) error {
+		kept = maxInt(kept, roll.img.Bounds().Dy())
+		for y := band.Rect.Min.Y; y < band.Rect.Max.Y; y++ {
+			img.Pix = append(img.Pix, band.Pix[band.PixOffset(band.Rect.Min.X, y):band.PixOffset(band.Rect.Max.X, y)]...)
+		}
+		img.Stride = 4 * roll.width
+		img.Rect.Max.Y += band.Rect.Dy()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	whole := NewRollCanvas(Millimeters(72))
+	whole.Write(tbl)
+	// the roll keeps a few bands and the row which is being drawn only
+	if whole := whole.Image().Rect.Dy(); kept > whole/10 {
+		t.Errorf("the roll keeps %d pixels of %d, the table is expected to be streamed", kept, whole)
+	}
+	if want := whole.Image(); img.Rect.Dy() != want.Rect.Dy() || !sameRows(img, want, 0, img.Rect.Dy()) {
+		t.Error("the table rendered in bands differs from the table drawn at once")
+	}
+}
@@ -0,0 +1,89 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"runtime"
+	"sync"
+)
+
+// BatchJob is the document rendered by RenderBatch
+type BatchJob struct {
+	Document DrawStruct
+	// Width is the width of the page, the page is as high as the document
+	Width Measure
+	// DPI is the resolution of the document, the one set by SetDPI is used if it is zero
+	DPI float64
+	// Output receives the image of the document, the image is not used by the batch after it returns
+	Output func(img *image.RGBA) error
+}
+
+// RenderBatch renders the documents concurrently by the number of workers (the number of CPUs if it is not positive)
+// and returns the errors of the documents in the order of the jobs, the error of the rendered document is nil.
+// Every document is drawn on its own canvas at its own resolution, the fonts are shared. When the context is done
+// the documents which are not rendered yet get the error of the context. The panic of the document is its error
+//
+//	errs := RenderBatch(ctx, 8, jobs)
+//	for i, err := range errs {
+//		if err != nil {
+//			log.Printf("receipt %d: %v", i, err)
+//		}
+//	}
+func RenderBatch(ctx context.Context, workers int, jobs []BatchJob) []error {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	var (
+		errs = make([]error, len(jobs))
+		// the default resolution is read once, the workers do not touch the package settings
+		resolution = dpi
+		queue      = make(chan int)
+		wg         sync.WaitGroup
+	)
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				errs[i] = renderJob(ctx, jobs[i], resolution)
+			}
+		}()
+	}
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return errs
+}
+
+// renderJob draws the document block by block on the roll, the context is checked between the blocks
+func renderJob(ctx context.Context, job BatchJob, defaultDPI float64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("rendering panicked: %v", r)
+		}
+	}()
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+	if job.Document == nil || job.Width == nil {
+		return errors.New("document and width expected")
+	}
+	if job.DPI <= 0 {
+		job.DPI = defaultDPI
+	}
+	roll := NewRollCanvasDPI(job.Width, job.DPI)
+	for _, block := range bandBlocks(job.Document) {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		roll.Write(block)
+	}
+	if err = roll.Close(); err != nil || job.Output == nil {
+		return err
+	}
+	return job.Output(roll.Image())
+}
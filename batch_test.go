@@ -0,0 +1,100 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"strconv"
+	"testing"
+)
+
+type panicStruct struct{}
+
+func (panicStruct) WriteTo(Canvas, image.Rectangle) image.Point {
+	panic("broken document")
+}
+
+func testDocument(i int) DrawStruct {
+	return Lines(Text("Receipt "+strconv.Itoa(i)), HRule(NewPen(color.Black, Pixels(1)), Pixels(0), LineSolid), Text("Total"))
+}
+
+func TestRenderBatchDPI(t *testing.T) {
+	var (
+		resolutions = []float64{203, 300, 180}
+		jobs        = make([]BatchJob, 30)
+		heights     = make([]int, len(jobs))
+	)
+	for i := range jobs {
+		i := i
+		jobs[i] = BatchJob{
+			Document: testDocument(i),
+			Width:    Millimeters(72),
+			DPI:      resolutions[i%len(resolutions)],
+			Output: func(img *image.RGBA) error {
+				heights[i] = img.Rect.Dy()
+				return nil
+			},
+		}
+	}
+	for i, err := range RenderBatch(context.Background(), 4, jobs) {
+		if err != nil {
+			t.Fatalf("job %d: %v", i, err)
+		}
+	}
+	for i, job := range jobs {
+		// the same document drawn alone at the same resolution
+		roll := NewRollCanvasDPI(job.Width, job.DPI)
+		roll.Write(job.Document)
+		if want := roll.Image().Rect.Dy(); heights[i] != want {
+			t.Errorf("job %d at %g dpi is %d pixels high, %d expected", i, job.DPI, heights[i], want)
+		}
+	}
+}
+
+func TestRenderBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	jobs := make([]BatchJob, 5)
+	for i := range jobs {
+		jobs[i] = BatchJob{
+			Document: testDocument(i),
+			Width:    Millimeters(72),
+			Output: func(*image.RGBA) error {
+				t.Error("the cancelled job is rendered")
+				return nil
+			},
+		}
+	}
+	for i, err := range RenderBatch(ctx, 2, jobs) {
+		if !errors.Is(err, ctx.Err()) {
+			t.Errorf("job %d: %v, %v expected", i, err, ctx.Err())
+		}
+	}
+}
+
+func TestRenderBatchPanic(t *testing.T) {
+	var (
+		rendered int
+		output   = func(*image.RGBA) error {
+			rendered++
+			return nil
+		}
+		jobs = []BatchJob{
+			{Document: testDocument(0), Width: Millimeters(72), Output: output},
+			{Document: panicStruct{}, Width: Millimeters(72), Output: output},
+			{Document: testDocument(2), Width: Millimeters(72), Output: output},
+		}
+	)
+	// one worker, so the counter is not shared by the goroutines
+	errs := RenderBatch(context.Background(), 1, jobs)
+	if errs[1] == nil {
+		t.Error("the panic of the document is not reported")
+	}
+	if errs[0] != nil || errs[2] != nil {
+		t.Errorf("the other documents failed: %v, %v", errs[0], errs[2])
+	}
+	if rendered != 2 {
+		t.Errorf("%d documents are rendered, 2 expected", rendered)
+	}
+}
@@ -0,0 +1,404 @@
+package receipt
+
+import (
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/vector"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+type (
+	// DrawBox is the frame around the object which can be customized with box options
+	DrawBox interface {
+		WriteTo(Canvas, image.Rectangle) image.Point
+		WithOptions(options ...BoxOption) DrawBox
+	}
+	// BoxOption changes the look of the Box:
+	//  OptionBackground, OptionBoxBorder, OptionLineStyle, OptionRadius, OptionPadding
+	BoxOption interface {
+		boxOptInt() int
+	}
+	// BackgroundOption is the option of both table cells and boxes
+	BackgroundOption interface {
+		cellOptInt() int
+		boxOptInt() int
+	}
+	// LineStyle is the pattern the lines are drawn with:
+	//  LineSolid, LineDashed, LineDotted, LineDouble
+	LineStyle int
+
+	box struct {
+		draw       DrawStruct
+		background color.Color
+		// the pens of the sides in the order of BorderSide bits: left, top, right, bottom
+		pens    [4]pen
+		style   LineStyle
+		radius  Measure
+		padding [4]Measure
+	}
+	boxBorder struct {
+		sides  BorderSide
+		usePen pen
+	}
+	boxLineStyle struct {
+		style LineStyle
+	}
+	boxRadius struct {
+		radius Measure
+	}
+	boxPadding struct {
+		padding [4]Measure
+	}
+)
+
+const (
+	LineSolid LineStyle = iota
+	LineDashed
+	LineDotted
+	LineDouble
+)
+
+// Box draws the frame around the object, the frame takes the whole width of the rectangle
+// and the height of the object. By default all the sides are drawn with the default pen
+//
+//	Box(totals).WithOptions(OptionBoxBorder(BorderAll, NewPen(color.Black, Pixels(3))), OptionRadius(Millimeters(2)), OptionPadding(Millimeters(1), Millimeters(1), Millimeters(1), Millimeters(1)))
+func Box(d DrawStruct) DrawBox {
+	return box{
+		draw:    d,
+		pens:    [4]pen{defaultPen, defaultPen, defaultPen, defaultPen},
+		radius:  pixels(0),
+		padding: [4]Measure{pixels(0), pixels(0), pixels(0), pixels(0)},
+	}
+}
+
+// OptionBoxBorder sets the pen of the sides of the box, the pen with zero weight hides the sides, e.g.
+//
+//	OptionBoxBorder(BorderLeft|BorderRight, NewPen(color.Black, Pixels(0)))
+func OptionBoxBorder(sides BorderSide, usePen pen) BoxOption {
+	return boxBorder{sides: sides, usePen: usePen}
+}
+
+// OptionLineStyle sets the pattern of the border lines, LineSolid by default
+func OptionLineStyle(style LineStyle) BoxOption {
+	return boxLineStyle{style: style}
+}
+
+// OptionRadius rounds the corners of the box
+func OptionRadius(radius Measure) BoxOption {
+	return boxRadius{radius: radius}
+}
+
+// OptionPadding sets the space between the border of the box and its content
+func OptionPadding(l, t, r, b Measure) BoxOption {
+	return boxPadding{padding: [4]Measure{l, t, r, b}}
+}
+
+func (_ cellBackground) boxOptInt() int {
+	return 0
+}
+
+func (_ boxBorder) boxOptInt() int {
+	return 0
+}
+
+func (_ boxLineStyle) boxOptInt() int {
+	return 0
+}
+
+func (_ boxRadius) boxOptInt() int {
+	return 0
+}
+
+func (_ boxPadding) boxOptInt() int {
+	return 0
+}
+
+func (b box) WithOptions(options ...BoxOption) DrawBox {
+	for _, opt := range options {
+		switch v := opt.(type) {
+		case cellBackground:
+			b.background = v.color
+		case boxBorder:
+			for i := range b.pens {
+				if v.sides&(1<<i) != 0 {
+					b.pens[i] = v.usePen
+				}
+			}
+		case boxLineStyle:
+			b.style = v.style
+		case boxRadius:
+			b.radius = v.radius
+		case boxPadding:
+			b.padding = v.padding
+		}
+	}
+	return b
+}
+
+// sideWidths returns the widths of the border sides in the units of the target,
+// every drawn side of the text box takes one character
+func (b box) sideWidths(m measurer) [4]int {
+	var widths [4]int
+	for i, p := range b.pens {
+		w := p.weight(m.DPI())
+		if w <= 0 {
+			continue
+		}
+		widths[i] = w
+		if _, ok := m.(*TextCanvas); ok {
+			widths[i] = 1
+		}
+	}
+	return widths
+}
+
+func (b box) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	var (
+		widths = b.sideWidths(canvas)
+		left   = widths[0] + canvas.toPixel(b.padding[0])
+		top    = widths[1] + canvas.toPixel(b.padding[1])
+		right  = widths[2] + canvas.toPixel(b.padding[2])
+		bottom = widths[3] + canvas.toPixel(b.padding[3])
+		inner  = image.Rect(rect.Min.X+left, rect.Min.Y+top, rect.Max.X-right, maxInt(rect.Max.Y-bottom, rect.Min.Y+top))
+	)
+	if _, ok := canvas.img.(nullImage); ok {
+		return image.Point{X: rect.Max.X, Y: b.draw.WriteTo(canvas, inner).Y + bottom}
+	}
+	// the background is painted under the content, so the height of the content is measured first
+	frame := image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, b.draw.WriteTo(canvas.measure(), inner).Y+bottom)
+	if b.background != nil {
+		b.paintBackground(canvas, frame, canvas.toPixel(b.radius))
+	}
+	frame.Max.Y = b.draw.WriteTo(canvas, inner).Y + bottom
+	b.paintBorder(canvas, frame, canvas.toPixel(b.radius), widths)
+	return image.Point{X: rect.Max.X, Y: frame.Max.Y}
+}
+
+func (b box) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	var (
+		widths = b.sideWidths(c)
+		top    = widths[1] + c.toLines(b.padding[1])
+		inner  = image.Rect(
+			rect.Min.X+widths[0]+c.toColumns(b.padding[0]),
+			rect.Min.Y+top,
+			rect.Max.X-widths[2]-c.toColumns(b.padding[2]),
+			maxInt(rect.Max.Y-widths[3]-c.toLines(b.padding[3]), rect.Min.Y+top),
+		)
+		bottom = c.writeStruct(b.draw, inner).Y + c.toLines(b.padding[3])
+		frame  = image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X-1, bottom-1+widths[3])
+	)
+	// there are no backgrounds, dashes and rounded corners for text
+	if widths[0] > 0 {
+		c.vLine(frame.Min.X, frame.Min.Y, frame.Max.Y)
+	}
+	if widths[1] > 0 {
+		c.hLine(frame.Min.Y, frame.Min.X, frame.Max.X)
+	}
+	if widths[2] > 0 {
+		c.vLine(frame.Max.X, frame.Min.Y, frame.Max.Y)
+	}
+	if widths[3] > 0 {
+		c.hLine(frame.Max.Y, frame.Min.X, frame.Max.X)
+	}
+	return image.Point{X: rect.Max.X, Y: bottom + widths[3]}
+}
+
+func (b box) measureContent(m measurer) (minWidth, maxWidth int) {
+	var (
+		widths     = b.sideWidths(m)
+		pad        = widths[0] + widths[2] + m.toWidth(b.padding[0]) + m.toWidth(b.padding[2])
+		cMin, cMax = measureStruct(m, b.draw)
+	)
+	return cMin + pad, cMax + pad
+}
+
+func (b box) defaultOptions(options ...TextOption) DrawStruct {
+	b.draw = withDefaultOptions(b.draw, options)
+	return b
+}
+
+// edge returns the outline of the border at the depth, which is the part of the widths of the sides:
+// 0 is the outer edge and 1 is the inner one. The radius of the corners (in pixels) is reduced by the depth
+func edge(size image.Point, cornerRadius int, widths [4]int, depth float64) []f64.Vec2 {
+	var (
+		radius = math.Min(float64(cornerRadius), float64(minInt(size.X, size.Y))/2)
+		inset  [4]float64
+		radii  [4]f64.Vec2
+		// the sides of the corners which reduce the horizontal and the vertical radius
+		xSides = [4]int{0, 2, 2, 0}
+		ySides = [4]int{1, 1, 3, 3}
+	)
+	for i, w := range widths {
+		inset[i] = float64(w) * depth
+	}
+	for i := range radii {
+		radii[i] = f64.Vec2{
+			math.Max(radius-inset[xSides[i]], 0),
+			math.Max(radius-inset[ySides[i]], 0),
+		}
+	}
+	return roundedRect(
+		f64.Vec2{inset[0], inset[1]},
+		f64.Vec2{float64(size.X) - inset[2], float64(size.Y) - inset[3]},
+		radii,
+	)
+}
+
+func (b box) paintBackground(canvas Canvas, rect image.Rectangle, radius int) {
+	size := rect.Size()
+	draw.DrawMask(canvas.img, rect, image.NewUniform(b.background), image.Point{}, canvas.fillMask(size, edge(size, radius, [4]int{}, 0)), image.Point{}, draw.Over)
+}
+
+// paintBorder draws the ring between the outer and the inner edges of the border. Every pixel of the ring
+// belongs to the side it is the nearest to (relative to the width of the side), so the corners of the sides
+// with different pens are split along the diagonal
+func (b box) paintBorder(canvas Canvas, rect image.Rectangle, radius int, widths [4]int) {
+	var (
+		size  = rect.Size()
+		rings = [][]f64.Vec2{edge(size, radius, widths, 0), reversePath(edge(size, radius, widths, 1))}
+		masks [4]*image.Alpha
+	)
+	if b.style == LineDouble {
+		// the double line takes the outer and the inner thirds of the border
+		rings = [][]f64.Vec2{
+			edge(size, radius, widths, 0), reversePath(edge(size, radius, widths, 1./3)),
+			edge(size, radius, widths, 2./3), reversePath(edge(size, radius, widths, 1)),
+		}
+	}
+	ring := canvas.fillMask(size, rings...)
+	for i := range masks {
+		if widths[i] > 0 {
+			masks[i] = image.NewAlpha(ring.Rect)
+		}
+	}
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			offset := ring.PixOffset(x, y)
+			if ring.Pix[offset] == 0 {
+				continue
+			}
+			var (
+				distances = [4]float64{float64(x) + .5, float64(y) + .5, float64(size.X-x) - .5, float64(size.Y-y) - .5}
+				side      = -1
+			)
+			for i, w := range widths {
+				if w > 0 && (side < 0 || distances[i]/float64(w) < distances[side]/float64(widths[side])) {
+					side = i
+				}
+			}
+			// the dashes of the horizontal sides go along x and the dashes of the vertical ones go along y
+			along := x
+			if side == 0 || side == 2 {
+				along = y
+			}
+			if side >= 0 && dashOn(b.style, along, widths[side]) {
+				masks[side].Pix[offset] = ring.Pix[offset]
+			}
+		}
+	}
+	for i, mask := range masks {
+		if mask != nil {
+			draw.DrawMask(canvas.img, rect, image.NewUniform(b.pens[i].color), image.Point{}, mask, image.Point{}, draw.Over)
+		}
+	}
+}
+
+// dashPattern returns the length of the dash and the period of the pattern of the line which is the width wide,
+// the dashes are three widths long and the dots are square
+func dashPattern(style LineStyle, width float64) (dash, period float64) {
+	width = math.Max(width, 1)
+	switch style {
+	case LineDashed:
+		return 3 * width, 5 * width
+	case LineDotted:
+		return width, 2 * width
+	default:
+		return 1, 1
+	}
+}
+
+// dashOn reports whether the point of the line at the distance from its start is painted
+func dashOn(style LineStyle, distance, width int) bool {
+	dash, period := dashPattern(style, float64(width))
+	return math.Mod(float64(distance), period) < dash
+}
+
+// roundedRect returns the clockwise outline of the rectangle with the elliptic corners,
+// the radii are listed clockwise from the top left corner
+func roundedRect(min, max f64.Vec2, radii [4]f64.Vec2) []f64.Vec2 {
+	var (
+		centers = [4]f64.Vec2{
+			{min[0] + radii[0][0], min[1] + radii[0][1]},
+			{max[0] - radii[1][0], min[1] + radii[1][1]},
+			{max[0] - radii[2][0], max[1] - radii[2][1]},
+			{min[0] + radii[3][0], max[1] - radii[3][1]},
+		}
+		outline = make([]f64.Vec2, 0, 4)
+	)
+	for i, c := range centers {
+		var (
+			// the arc of the top left corner starts on the left side, the y axis looks down
+			start = math.Pi + float64(i)*math.Pi/2
+			steps = arcSteps(math.Max(radii[i][0], radii[i][1]), math.Pi/2)
+		)
+		if steps == 0 {
+			outline = append(outline, c)
+			continue
+		}
+		for s := 0; s <= steps; s++ {
+			angle := start + float64(s)*math.Pi/2/float64(steps)
+			outline = append(outline, f64.Vec2{c[0] + radii[i][0]*math.Cos(angle), c[1] + radii[i][1]*math.Sin(angle)})
+		}
+	}
+	return outline
+}
+
+// arcSteps returns the number of the straight segments the arc of the radius is flattened into,
+// the segments deviate from the arc by no more than a quarter of a pixel
+func arcSteps(radius, angle float64) int {
+	if radius < 1 {
+		return 0
+	}
+	return int(math.Ceil(angle / (2 * math.Acos(1-.25/radius))))
+}
+
+func reversePath(path []f64.Vec2) []f64.Vec2 {
+	reversed := make([]f64.Vec2, len(path))
+	for i, p := range path {
+		reversed[len(path)-1-i] = p
+	}
+	return reversed
+}
+
+// fillMask rasterizes the polygons with anti-aliasing unless the canvas draws for the monochrome output
+func (c Canvas) fillMask(size image.Point, polygons ...[]f64.Vec2) *image.Alpha {
+	mask := fillMask(size, polygons...)
+	if c.mono != nil {
+		aliasMask(mask)
+	}
+	return mask
+}
+
+// fillMask rasterizes the closed polygons with anti-aliasing, the polygons going counterclockwise
+// cut the holes in the polygons going clockwise
+func fillMask(size image.Point, polygons ...[]f64.Vec2) *image.Alpha {
+	mask := image.NewAlpha(image.Rect(0, 0, size.X, size.Y))
+	if size.X <= 0 || size.Y <= 0 {
+		return mask
+	}
+	z := vector.NewRasterizer(size.X, size.Y)
+	for _, polygon := range polygons {
+		if len(polygon) < 3 {
+			continue
+		}
+		z.MoveTo(float32(polygon[0][0]), float32(polygon[0][1]))
+		for _, p := range polygon[1:] {
+			z.LineTo(float32(p[0]), float32(p[1]))
+		}
+		z.ClosePath()
+	}
+	z.Draw(mask, mask.Rect, image.Opaque, image.Point{})
+	return mask
+}
@@ -0,0 +1,166 @@
+package receipt
+
+import (
+	"strings"
+)
+
+type (
+	textDecimalSeparator struct {
+		separator rune
+	}
+	// textDecimalTab is the width of the fraction part set by OptionDecimalTab
+	textDecimalTab struct {
+		width Measure
+	}
+	// decimalTab is the width of the widest fraction part (with the separator) among the cells of the column,
+	// the table adds it to the text options of the cells aligned by AlignDecimal
+	decimalTab struct {
+		fraction int
+	}
+	decimalColumn struct {
+		aligned  bool
+		integer  int
+		fraction int
+		// padding is the widest padding of the aligned cells
+		padding int
+	}
+)
+
+const defaultDecimalSeparator = '.'
+
+// OptionDecimalSeparator sets the character on which the text aligned by AlignDecimal is lined up, the dot by default
+func OptionDecimalSeparator(separator rune) TextOption {
+	return textDecimalSeparator{separator: separator}
+}
+
+// OptionDecimalTab lines up the text aligned by AlignDecimal outside of the table: the separator stands at the width
+// from the right edge, so the texts with the same tab are lined up one under another, e.g.
+//
+//	tab := OptionDecimalTab(Millimeters(6))
+//	Lines(Text("1 200.5", OptionAlignment(AlignDecimal), tab), Text("36.00", OptionAlignment(AlignDecimal), tab))
+//
+// The width is the width of the widest fraction part with the separator. The table lines up its columns itself
+func OptionDecimalTab(fractionWidth Measure) TextOption {
+	return textDecimalTab{width: fractionWidth}
+}
+
+func (_ textDecimalTab) textOptInt() int {
+	return 0
+}
+
+func (_ textDecimalTab) tableColumnOptInt() int {
+	return 0
+}
+
+func (_ textDecimalTab) cellOptInt() int {
+	return 0
+}
+
+func (_ textDecimalSeparator) textOptInt() int {
+	return 0
+}
+
+func (_ textDecimalSeparator) tableColumnOptInt() int {
+	return 0
+}
+
+func (_ textDecimalSeparator) cellOptInt() int {
+	return 0
+}
+
+func (_ decimalTab) textOptInt() int {
+	return 0
+}
+
+func (_ decimalTab) tableColumnOptInt() int {
+	return 0
+}
+
+func (_ decimalTab) cellOptInt() int {
+	return 0
+}
+
+// splitDecimal splits the number into the integer part and the fraction part starting with the separator
+func splitDecimal(s string, separator rune) (integer, fraction string) {
+	if i := strings.IndexRune(s, separator); i >= 0 {
+		return s[:i], s[i:]
+	}
+	return s, ""
+}
+
+// decimalShift calculates how far the right-aligned text has to be moved to the left so that its separator
+// stands where the separators of the other cells of the column do (or at the tab of OptionDecimalTab).
+// Without the table and the tab the text is right-aligned
+func (s textStyle) decimalShift(m measurer, text string, width func(string) int) int {
+	if s.alignment.hAlign != AlignDecimal {
+		return 0
+	}
+	tab := s.fraction
+	if s.tab != nil {
+		tab = m.toWidth(s.tab)
+	}
+	_, fraction := splitDecimal(text, s.separator)
+	if shift := tab - width(fraction); shift > 0 {
+		return shift
+	}
+	return 0
+}
+
+// decimalColumns finds the widest integer and fraction parts among the cells of the body and the footer
+// which are aligned by AlignDecimal, the header is not taken into account
+func (t table) decimalColumns(m measurer) []decimalColumn {
+	var (
+		columns  = make([]decimalColumn, len(t.columns))
+		layout   = newTableLayout(t, nil)
+		occupied = make([]int, len(t.columns))
+	)
+	measureCells := func(cells []tableCell, rowOptions []CellOption) {
+		for _, cell := range cells {
+			if cell.span > 1 {
+				continue
+			}
+			var (
+				style        = layout.cellStyle(cell, rowOptions)
+				draw, padFnc = t.columns[cell.col].extractDrawStruct(cell.draw, style.text...)
+				txt, ok      = draw.(text)
+			)
+			if !ok {
+				continue
+			}
+			textStyle := txt.style()
+			if textStyle.alignment.hAlign != AlignDecimal {
+				continue
+			}
+			var (
+				integer, fraction = splitDecimal(txt.text, textStyle.separator)
+				_, integerWidth   = m.measureText(integer, textStyle)
+				_, fractionWidth  = m.measureText(fraction, textStyle)
+				_, textWidth      = m.measureText(txt.text, textStyle)
+				_, paddedWidth    = measureStruct(m, padFnc(draw))
+				column            = &columns[cell.col]
+			)
+			column.aligned = true
+			if integerWidth > column.integer {
+				column.integer = integerWidth
+			}
+			if fractionWidth > column.fraction {
+				column.fraction = fractionWidth
+			}
+			if paddedWidth-textWidth > column.padding {
+				column.padding = paddedWidth - textWidth
+			}
+		}
+	}
+	for i, row := range t.rows {
+		cells := splitTableRow(occupied, row.getColumnByNum)
+		occupyRows(occupied, cells)
+		measureCells(cells, t.rowOptions(i, row))
+	}
+	t.summaryRows(occupied, measureCells)
+	return columns
+}
+
+// width is the width of the column content when the numbers are lined up
+func (c decimalColumn) width() int {
+	return c.integer + c.fraction + c.padding
+}
@@ -0,0 +1,90 @@
+package receipt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecimalTabText(t *testing.T) {
+	var (
+		tab    = OptionDecimalTab(Millimeters(9))
+		align  = OptionAlignment(AlignDecimal)
+		canvas = NewTextCanvas(32, Millimeters(72))
+	)
+	canvas.Write(Lines(
+		Text("1 200.5", align, tab),
+		Text("36.00", align, tab),
+		Text("0.125", align, tab),
+		Text("7", align, tab),
+	))
+	var (
+		lines = strings.Split(strings.TrimRight(canvas.String(), "\n"), "\n")
+		// the column is 72/32 mm wide, so the tab of 9 mm takes the last 4 of 32 columns
+		want = 28
+	)
+	for _, line := range lines[:3] {
+		if got := strings.Index(line, "."); got != want {
+			t.Errorf("the separator of %q is at %d, %d expected", line, got, want)
+		}
+	}
+	// the integer stands right before the tab
+	if got := strings.Index(lines[3], "7"); got != want-1 {
+		t.Errorf("the integer %q ends at %d, %d expected", lines[3], got, want-1)
+	}
+}
+
+func TestDecimalTabPixels(t *testing.T) {
+	// the tab of 10 mm at 203 dpi is 80 pixels, so the separator starts at 520 pixels of 600
+	// and the ink of the dot starts 3 pixels later, after the left bearing of the glyph
+	const dot = 523
+	for _, s := range []string{"1 200.5", "36.00", "0.125"} {
+		canvas := testCanvas(600, 200)
+		canvas.SetDPI(203)
+		canvas.Write(Text(s, OptionAlignment(AlignDecimal), OptionDecimalTab(Millimeters(10))))
+		var (
+			columns = dotColumns(canvas)
+			found   bool
+		)
+		for i, x := range columns {
+			if x == dot && (i == 0 || columns[i-1] != dot-1) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("the separator of %q is not at %d, the dots are at the columns %v", s, dot, columns)
+		}
+	}
+}
+
+// dotColumns returns the columns of the ink which stands in the lower third of the text only, i.e. of the separator
+func dotColumns(canvas Canvas) []int {
+	var (
+		b           = canvas.img.Bounds()
+		tops        = make([]int, b.Dx())
+		top, bottom = b.Max.Y, b.Min.Y
+	)
+	for x := b.Min.X; x < b.Max.X; x++ {
+		tops[x-b.Min.X] = -1
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			if _, _, _, a := canvas.img.At(x, y).RGBA(); a < 0x8000 {
+				continue
+			}
+			if tops[x-b.Min.X] < 0 {
+				tops[x-b.Min.X] = y
+			}
+			if y < top {
+				top = y
+			}
+			if y > bottom {
+				bottom = y
+			}
+		}
+	}
+	var columns []int
+	for i, y := range tops {
+		if y >= 0 && y > bottom-(bottom-top)/3 {
+			columns = append(columns, b.Min.X+i)
+		}
+	}
+	return columns
+}
@@ -15,8 +15,10 @@ type (
 	Alignment int
 
 	pen struct {
-		color  color.Color
-		weight int
+		color color.Color
+		// width is converted to pixels at the resolution of the canvas the pen draws on
+		width     Measure
+		antialias bool
 	}
 	cellAlignment struct {
 		hAlign    Alignment
@@ -28,20 +30,47 @@ const (
 	AlignLeft Alignment = iota
 	AlignRight
 	AlignCenter
+	// AlignDecimal lines up the numbers of the table column on the decimal separator, see OptionDecimalSeparator
+	AlignDecimal
 )
 
 var defaultPen = pen{
-	color:  color.Black,
-	weight: 6,
+	color: color.Black,
+	width: pixels(6),
 }
 
 func NewPen(color color.Color, w Measure) pen {
 	return pen{
-		color:  color,
-		weight: w.toPixel(),
+		color: color,
+		width: w,
 	}
 }
 
+// Antialiased returns the pen which draws the borders of the exact thickness centered on the edges,
+// the pixels which are covered partly are blended with the background
+//
+//	OptionBorderPen(NewPen(color.Black, Millimeters(0.3)).Antialiased())
+func (p pen) Antialiased() pen {
+	p.antialias = true
+	return p
+}
+
+// weight returns the thickness of the line in whole pixels at the resolution
+func (p pen) weight(dpi float64) int {
+	if p.width == nil {
+		return 0
+	}
+	return p.width.toPixel(dpi)
+}
+
+// thickness returns the exact thickness of the line in pixels at the resolution, which is used by the anti-aliased pen
+func (p pen) thickness(dpi float64) float64 {
+	if p.width == nil {
+		return 0
+	}
+	return toPixelF(p.width, dpi)
+}
+
 func (p pen) textOptInt() int {
 	return 0
 }
@@ -50,20 +79,30 @@ func (p pen) tableColumnOptInt() int {
 	return 0
 }
 
+func (p pen) cellOptInt() int {
+	return 0
+}
+
 func makeFontDrawer(
 	dst draw.Image,
 	fontData *truetype.Font,
 	fontColor color.Color,
 	fontSize float64,
+	dpi float64,
 ) *font.Drawer {
 	return &font.Drawer{
-		Dst: dst,
-		Src: image.NewUniform(fontColor),
-		Face: truetype.NewFace(fontData, &truetype.Options{
-			Size:    fontSize,
-			Hinting: font.HintingFull,
-			DPI:     dpi,
-		}),
+		Dst:  dst,
+		Src:  image.NewUniform(fontColor),
+		Face: newFace(fontData, fontSize, dpi, false),
+	}
+}
+
+// fontDrawer makes the drawer of the text style, the faces are reused by the canvas
+func (c Canvas) fontDrawer(dst draw.Image, style textStyle) *font.Drawer {
+	return &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(style.usePen.color),
+		Face: c.faces.face(style.font, style.fontSize, c.dpi, c.mono != nil),
 	}
 }
 
@@ -74,7 +113,7 @@ func calcTextPositionX(
 ) fixed.Int26_6 {
 	xPosition := fixed.I(rect.Min.X)
 	switch align.hAlign {
-	case AlignRight:
+	case AlignRight, AlignDecimal:
 		xPosition = fixed.I(rect.Max.X) - textWidth
 	case AlignCenter:
 		xPosition += fixed.I((rect.Max.X-rect.Min.X)/2) - fixed.I(textWidth.Ceil()/2)
@@ -191,17 +230,132 @@ func fillTextIntoRect(
 	return yPosition.Ceil()
 }
 
-func drawRect(img draw.Image, rect image.Rectangle, usePen pen) {
-	for x := rect.Min.X; x <= rect.Max.X; x++ {
-		for t := 0; t < usePen.weight; t++ {
-			img.Set(x, rect.Min.Y+t, usePen.color)
-			img.Set(x, rect.Max.Y+t, usePen.color)
+func drawRect(canvas Canvas, rect image.Rectangle, usePen pen) {
+	drawRectSides(canvas, rect, usePen, BorderAll)
+}
+
+// drawRectSides strokes the sides of the rectangle, the strokes are centered on the edges. The edge is the row
+// (or the column) of pixels at the coordinate, the strokes of the adjacent sides overlap in the corners
+func drawRectSides(canvas Canvas, rect image.Rectangle, usePen pen, sides BorderSide) {
+	if usePen.antialias && canvas.mono == nil {
+		drawRectSidesAA(canvas.img, rect, usePen.color, usePen.thickness(canvas.dpi), sides)
+		return
+	}
+	var (
+		img    = canvas.img
+		weight = usePen.weight(canvas.dpi)
+		src    = image.NewUniform(usePen.color)
+		// the stroke of the odd weight is centered exactly, the even one is shifted by half a pixel up and left
+		lo = func(c int) int {
+			return c - (weight-1)/2
+		}
+		hi = func(c int) int {
+			return lo(c) + weight
+		}
+	)
+	if weight <= 0 {
+		return
+	}
+	if sides&BorderTop != 0 {
+		draw.Draw(img, image.Rect(lo(rect.Min.X), lo(rect.Min.Y), hi(rect.Max.X), hi(rect.Min.Y)), src, image.Point{}, draw.Src)
+	}
+	if sides&BorderBottom != 0 {
+		draw.Draw(img, image.Rect(lo(rect.Min.X), lo(rect.Max.Y), hi(rect.Max.X), hi(rect.Max.Y)), src, image.Point{}, draw.Src)
+	}
+	if sides&BorderLeft != 0 {
+		draw.Draw(img, image.Rect(lo(rect.Min.X), lo(rect.Min.Y), hi(rect.Min.X), hi(rect.Max.Y)), src, image.Point{}, draw.Src)
+	}
+	if sides&BorderRight != 0 {
+		draw.Draw(img, image.Rect(lo(rect.Max.X), lo(rect.Min.Y), hi(rect.Max.X), hi(rect.Max.Y)), src, image.Point{}, draw.Src)
+	}
+}
+
+// drawRectSidesAA strokes the sides with the exact thickness centered on the middles of the edges,
+// the pixels covered partly are blended. Every pixel is drawn once, so the corners are not blended twice
+func drawRectSidesAA(img draw.Image, rect image.Rectangle, c color.Color, thickness float64, sides BorderSide) {
+	var (
+		half = thickness / 2
+		// the middles of the edges
+		x0, y0 = float64(rect.Min.X) + .5, float64(rect.Min.Y) + .5
+		x1, y1 = float64(rect.Max.X) + .5, float64(rect.Max.Y) + .5
+		// the strips of the sides: min x, min y, max x, max y
+		strips [][4]float64
+	)
+	if half <= 0 {
+		return
+	}
+	if sides&BorderTop != 0 {
+		strips = append(strips, [4]float64{x0 - half, y0 - half, x1 + half, y0 + half})
+	}
+	if sides&BorderBottom != 0 {
+		strips = append(strips, [4]float64{x0 - half, y1 - half, x1 + half, y1 + half})
+	}
+	if sides&BorderLeft != 0 {
+		strips = append(strips, [4]float64{x0 - half, y0 - half, x0 + half, y1 + half})
+	}
+	if sides&BorderRight != 0 {
+		strips = append(strips, [4]float64{x1 - half, y0 - half, x1 + half, y1 + half})
+	}
+	var (
+		src        = image.NewUniform(c)
+		_, _, _, a = c.RGBA()
+		drawn      []image.Rectangle
+	)
+	for _, s := range strips {
+		var (
+			r    = image.Rect(int(math.Floor(s[0])), int(math.Floor(s[1])), int(math.Ceil(s[2])), int(math.Ceil(s[3])))
+			mask = image.NewAlpha(r)
+		)
+		// the corners are covered by the adjacent strips too, the pixel takes the largest coverage
+		for _, other := range strips {
+			coverStrip(mask, other)
+		}
+		// the corners drawn with the previous strips are skipped
+		for _, d := range drawn {
+			clearAlpha(mask, d.Intersect(r))
+		}
+		if a == 0xffff {
+			// the opaque color replaces the pixels which are covered entirely, it is faster than blending
+			solid := image.Rect(int(math.Ceil(s[0])), int(math.Ceil(s[1])), int(math.Floor(s[2])), int(math.Floor(s[3])))
+			draw.Draw(img, solid, src, image.Point{}, draw.Src)
+			clearAlpha(mask, solid)
+		}
+		draw.DrawMask(img, r, src, image.Point{}, mask, r.Min, draw.Over)
+		drawn = append(drawn, r)
+	}
+}
+
+// coverStrip sets the coverage of the pixels of the mask by the strip (min x, min y, max x, max y) unless
+// they are covered more. The coverage of the pixel is the product of the coverages of its column and its row
+func coverStrip(mask *image.Alpha, s [4]float64) {
+	var (
+		r       = image.Rect(int(math.Floor(s[0])), int(math.Floor(s[1])), int(math.Ceil(s[2])), int(math.Ceil(s[3]))).Intersect(mask.Rect)
+		columns = make([]uint32, r.Dx())
+	)
+	for i := range columns {
+		x := float64(r.Min.X + i)
+		columns[i] = uint32(math.Round((math.Min(s[2], x+1) - math.Max(s[0], x)) * 0xff))
+	}
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		var (
+			cy  = uint32(math.Round((math.Min(s[3], float64(y+1)) - math.Max(s[1], float64(y))) * 0xff))
+			row = mask.Pix[mask.PixOffset(r.Min.X, y):mask.PixOffset(r.Max.X, y)]
+		)
+		for i, cx := range columns {
+			if a := uint8(cx * cy / 0xff); a > row[i] {
+				row[i] = a
+			}
 		}
 	}
-	for y := rect.Min.Y; y <= rect.Max.Y; y++ {
-		for t := 0; t < usePen.weight; t++ {
-			img.Set(rect.Min.X+t, y, usePen.color)
-			img.Set(rect.Max.X+t, y, usePen.color)
+}
+
+// clearAlpha makes the pixels of the mask in the rectangle transparent
+func clearAlpha(mask *image.Alpha, r image.Rectangle) {
+	r = r.Intersect(mask.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		row := mask.Pix[mask.PixOffset(r.Min.X, y):mask.PixOffset(r.Max.X, y)]
+		for i := range row {
+			row[i] = 0
 		}
 	}
 }
@@ -0,0 +1,106 @@
+package receipt
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// baselineRectSides is the implementation which set the pixels of the borders one by one,
+// it is kept for the comparison in BenchmarkDrawRectSides
+func baselineRectSides(img draw.Image, rect image.Rectangle, c color.Color, weight int, sides BorderSide) {
+	for x := rect.Min.X; x <= rect.Max.X; x++ {
+		for t := 0; t < weight; t++ {
+			if sides&BorderTop != 0 {
+				img.Set(x, rect.Min.Y+t, c)
+			}
+			if sides&BorderBottom != 0 {
+				img.Set(x, rect.Max.Y+t, c)
+			}
+		}
+	}
+	for y := rect.Min.Y; y <= rect.Max.Y; y++ {
+		for t := 0; t < weight; t++ {
+			if sides&BorderLeft != 0 {
+				img.Set(rect.Min.X+t, y, c)
+			}
+			if sides&BorderRight != 0 {
+				img.Set(rect.Max.X+t, y, c)
+			}
+		}
+	}
+}
+
+func TestDrawRectSidesCentered(t *testing.T) {
+	const edge = 20
+	for w := 1; w <= 5; w++ {
+		canvas := testCanvas(60, 60)
+		drawRectSides(canvas, image.Rect(edge, edge, 40, 40), NewPen(color.Black, Pixels(w)), BorderAll)
+		lo := edge - (w-1)/2
+		for y := edge - w; y <= edge+w; y++ {
+			var (
+				_, _, _, a = canvas.img.At(30, y).RGBA()
+				inside     = y >= lo && y < lo+w
+			)
+			if inside != (a == 0xffff) {
+				t.Errorf("weight %d: the pixel at the row %d is painted %t, %t expected", w, y, a == 0xffff, inside)
+			}
+		}
+	}
+}
+
+func TestDrawRectSidesAACentered(t *testing.T) {
+	const edge = 20
+	for _, w := range []float64{1, 1.5, 2, 2.4, 3} {
+		var (
+			canvas = testCanvas(60, 60)
+			alpha  = func(y int) int {
+				_, _, _, a := canvas.img.At(30, y).RGBA()
+				return int(a >> 8)
+			}
+			total int
+		)
+		drawRectSidesAA(canvas.img, image.Rect(edge, edge, 40, 40), color.Black, w, BorderAll)
+		for k := 0; k <= 3; k++ {
+			if up, down := alpha(edge-k), alpha(edge+k); up != down {
+				t.Errorf("thickness %g: the stroke is not centered on the edge, %d above and %d below at %d", w, up, down, k)
+			}
+			total += alpha(edge - k)
+			if k > 0 {
+				total += alpha(edge + k)
+			}
+		}
+		if want := int(w * 0xff); total < want-2 || total > want+2 {
+			t.Errorf("thickness %g: the stroke covers %d, %d expected", w, total, want)
+		}
+	}
+}
+
+func BenchmarkDrawRectSides(b *testing.B) {
+	var (
+		canvas = testCanvas(1000, 1000)
+		rect   = image.Rect(10, 10, 990, 990)
+	)
+	canvas.SetDPI(203)
+	pens := []struct {
+		name string
+		pen  pen
+	}{
+		{name: "plain", pen: NewPen(color.Black, Millimeters(0.3))},
+		{name: "antialiased", pen: NewPen(color.Black, Millimeters(0.3)).Antialiased()},
+	}
+	b.Run("baseline", func(b *testing.B) {
+		weight := Millimeters(0.3).toPixel(canvas.dpi)
+		for i := 0; i < b.N; i++ {
+			baselineRectSides(canvas.img, rect, color.Black, weight, BorderAll)
+		}
+	})
+	for _, p := range pens {
+		b.Run(p.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				drawRectSides(canvas, rect, p.pen, BorderAll)
+			}
+		})
+	}
+}
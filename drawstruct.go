@@ -2,14 +2,21 @@ package receipt
 
 import (
 	"image"
+	"image/color"
 	"image/draw"
 )
 
 type (
 	Canvas struct {
-		img   draw.Image
-		point image.Point
-		rect  image.Rectangle
+		img    draw.Image
+		point  image.Point
+		rect   image.Rectangle
+		layers *layers
+		// dpi is the resolution the measures are converted to pixels at
+		dpi   float64
+		faces *faceCache
+		// mono is set when the canvas draws for the monochrome output, see SetMonochrome
+		mono *monochromeSettings
 	}
 	DrawStruct interface {
 		WriteTo(Canvas, image.Rectangle) image.Point
@@ -24,15 +31,46 @@ type (
 	cols struct {
 		cols []DrawStruct
 	}
+	// nullImage has empty bounds, so drawing operations clip everything out of it
+	nullImage struct{}
 )
 
+// measure returns the copy of the canvas which does not draw anything,
+// it is used to find out the size of the objects before they are drawn
+func (c Canvas) measure() Canvas {
+	c.img = nullImage{}
+	return c
+}
+
 func NewCanvas(img draw.Image, rect image.Rectangle) Canvas {
 	return Canvas{
-		img:  img,
-		rect: rect,
+		img:    img,
+		rect:   rect,
+		layers: &layers{},
+		dpi:    dpi,
+		faces:  newFaceCache(),
 	}
 }
 
+// SetDPI sets the resolution (dots per inch) of the canvas, the one set by the package SetDPI is used by default.
+// The canvases of different resolutions can be drawn concurrently
+//
+//	canvas := NewCanvas(img, img.Bounds())
+//	canvas.SetDPI(203)
+func (c *Canvas) SetDPI(dpi float64) {
+	c.dpi = dpi
+}
+
+// DPI returns the resolution of the canvas
+func (c Canvas) DPI() float64 {
+	return c.dpi
+}
+
+// toPixel converts the measure to pixels at the resolution of the canvas
+func (c Canvas) toPixel(m Measure) int {
+	return m.toPixel(c.dpi)
+}
+
 // Write will draw the block of objects, starting from the vertical position
 // at which drawing of the previous block of objects was completed
 func (c *Canvas) Write(d DrawStruct) image.Point {
@@ -60,6 +98,28 @@ func (l lines) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
 	return image.Point{X: rect.Max.X, Y: rect.Max.Y}
 }
 
+func (l lines) measureContent(m measurer) (minWidth, maxWidth int) {
+	for _, d := range l.lines {
+		cMin, cMax := measureStruct(m, d)
+		if cMin > minWidth {
+			minWidth = cMin
+		}
+		if cMax > maxWidth {
+			maxWidth = cMax
+		}
+	}
+	return minWidth, maxWidth
+}
+
+// defaultOptions passes the options (e.g. the font of the table column) to the texts of the lines
+func (l lines) defaultOptions(options ...TextOption) DrawStruct {
+	result := lines{lines: make([]DrawStruct, len(l.lines))}
+	for i, d := range l.lines {
+		result.lines[i] = withDefaultOptions(d, options)
+	}
+	return result
+}
+
 func Cols(d ...DrawStruct) DrawColumns {
 	return cols{}.Add(d...)
 }
@@ -71,16 +131,69 @@ func (c cols) Add(d ...DrawStruct) cols {
 }
 
 func (c cols) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
-	bottom := rect.Max.Y
-	for _, d := range c.cols {
-		point := d.WriteTo(canvas, rect)
-		rect.Min.X = point.X
+	var (
+		bottom  = rect.Max.Y
+		left    = rect.Min.X
+		columns = make([]image.Rectangle, len(c.cols))
+	)
+	for i, w := range c.widths(canvas, rect.Dx()) {
+		columns[i] = image.Rect(left, rect.Min.Y, left+w, rect.Max.Y)
+		left += w
+		if fillsRow(c.cols[i]) {
+			continue
+		}
+		point := c.cols[i].WriteTo(canvas, columns[i])
 		if bottom < point.Y {
 			bottom = point.Y
-			rect.Max.Y = bottom
 		}
 	}
-	return image.Point{X: rect.Max.X, Y: rect.Max.Y}
+	// the height of the row is known when the other columns are drawn
+	for i, d := range c.cols {
+		if fillsRow(d) {
+			columns[i].Max.Y = bottom
+			d.WriteTo(canvas, columns[i])
+		}
+	}
+	return image.Point{X: rect.Max.X, Y: bottom}
+}
+
+// widths calculates the widths of the children before they are drawn, see ColWidth
+func (c cols) widths(m measurer, width int) []int {
+	tracks := make([]sizeTrack, len(c.cols))
+	for i, d := range c.cols {
+		tracks[i].size = childSize(d)
+		if _, ok := tracks[i].size.(sizeFraction); !ok {
+			tracks[i].minContent, tracks[i].maxContent = measureStruct(m, d)
+		}
+	}
+	return distributeSizes(width, tracks)
+}
+
+func (c cols) measureContent(m measurer) (minWidth, maxWidth int) {
+	for _, d := range c.cols {
+		cMin, cMax := measureStruct(m, d)
+		minWidth += cMin
+		maxWidth += cMax
+	}
+	return minWidth, maxWidth
+}
+
+// defaultOptions passes the options (e.g. the font of the table column) to the texts of the columns
+func (c cols) defaultOptions(options ...TextOption) DrawStruct {
+	result := cols{cols: make([]DrawStruct, len(c.cols))}
+	for i, d := range c.cols {
+		result.cols[i] = withDefaultOptions(d, options)
+	}
+	return result
+}
+
+// withDefaultOptions applies the options to the text or to the texts of the container,
+// the options already set take precedence
+func withDefaultOptions(d DrawStruct, options []TextOption) DrawStruct {
+	if c, ok := d.(textContainer); ok {
+		return c.defaultOptions(options...)
+	}
+	return d
 }
 
 func (c cols) getColumnByNum(num int) DrawStruct {
@@ -89,3 +202,17 @@ func (c cols) getColumnByNum(num int) DrawStruct {
 	}
 	return Empty()
 }
+
+func (nullImage) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+func (nullImage) Bounds() image.Rectangle {
+	return image.Rectangle{}
+}
+
+func (nullImage) At(int, int) color.Color {
+	return color.Transparent
+}
+
+func (nullImage) Set(int, int, color.Color) {}
@@ -64,7 +64,7 @@ func main() {
 		cg.PaddingLeftRight(cg.Millimeters(10), cg.Lines(
 			cg.Cols(
 				cg.Text(fmt.Sprintf("Order #%d %s", rand.Int(), time.Now().Format("01.02.2006 15:04")), cg.OptionAlignment(cg.AlignLeft), fontOpt),
-				cg.Text("DRAFT", cg.OptionAlignment(cg.AlignRight), fontAccentOpt),
+				cg.ColWidth(cg.Text("DRAFT", cg.OptionAlignment(cg.AlignRight), fontAccentOpt), cg.SizeFixed(cg.Millimeters(30))),
 			),
 			cg.Text("555-345-65-66 Menshenin Igor", fontOpt),
 		)),
@@ -107,20 +107,25 @@ func main() {
 				cg.Text("600.00"),
 				cg.Text("120.00"),
 			),
-			cg.Cols(
-				cg.Empty(),
-				cg.ColSpan(cg.Text("1440.00", tableBoldFont, cg.OptionAlignment(cg.AlignRight)), 3),
-				cg.ColSpan(cg.Text("1200.00", tableBoldFont, cg.OptionAlignment(cg.AlignRight)), 2),
-				cg.Text("120.00", tableBoldFont, cg.OptionAlignment(cg.AlignRight)),
-			),
-			cg.Cols(
-				cg.Text("Total including delivery and fee (retail)", tableBoldFont, cg.OptionAlignment(cg.AlignRight)),
-				cg.ColSpan(cg.Text("1977.37", tableBoldFont, cg.OptionAlignment(cg.AlignRight)), 6),
-			),
-			cg.Cols(
-				cg.Text("Total including delivery and fee (wholesale)", tableBoldFont, cg.OptionAlignment(cg.AlignRight)),
-				cg.ColSpan(cg.Text("1812.22", tableBoldFont, cg.OptionAlignment(cg.AlignRight)), 6),
+		).WithOptions(
+			// the sums are calculated over the AMOUNT, AMOUNT WS and DELIVERY columns
+			cg.OptionFooter(
+				cg.Cols(
+					cg.Empty(),
+					cg.ColSpan(cg.FooterSum(), 3),
+					cg.ColSpan(cg.FooterSum(), 2),
+					cg.FooterSum(),
+				),
+				cg.Cols(
+					cg.Text("Total including delivery and fee (retail)"),
+					cg.ColSpan(cg.Text("1977.37"), 6),
+				),
+				cg.Cols(
+					cg.Text("Total including delivery and fee (wholesale)"),
+					cg.ColSpan(cg.Text("1812.22"), 6),
+				),
 			),
+			cg.OptionFooterStyle(tableBoldFont, cg.OptionAlignment(cg.AlignRight)),
 		),
 	)))
 
@@ -10,30 +10,35 @@ type (
 		drawContent() DrawStruct
 	}
 	fixedFiller struct {
-		x int
-		y int
+		x Measure
+		y Measure
 	}
 	padding struct {
-		paddingRight  int
-		paddingTop    int
-		paddingLeft   int
-		paddingBottom int
+		paddingRight  Measure
+		paddingTop    Measure
+		paddingLeft   Measure
+		paddingBottom Measure
 		content       DrawStruct
 	}
 	empty struct{}
 )
 
 func Fixed(x, y Measure) DrawStruct {
-	return fixedFiller{x: x.toPixel(), y: y.toPixel()}
+	return fixedFiller{x: x, y: y}
 }
 
 func (f fixedFiller) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
 	return image.Point{
-		X: rect.Min.X + f.x,
-		Y: rect.Min.Y + f.y,
+		X: rect.Min.X + canvas.toPixel(f.x),
+		Y: rect.Min.Y + canvas.toPixel(f.y),
 	}
 }
 
+func (f fixedFiller) measureContent(m measurer) (minWidth, maxWidth int) {
+	w := m.toWidth(f.x)
+	return w, w
+}
+
 func FixedY(y Measure) DrawStruct {
 	return Fixed(pixels(0), y)
 }
@@ -41,10 +46,10 @@ func FixedY(y Measure) DrawStruct {
 // PaddingLeftRight adds padding on all sides to the drawing object
 func Padding4(pad Measure, d DrawStruct) DrawStruct {
 	return padding{
-		paddingRight:  pad.toPixel(),
-		paddingTop:    pad.toPixel(),
-		paddingLeft:   pad.toPixel(),
-		paddingBottom: pad.toPixel(),
+		paddingRight:  pad,
+		paddingTop:    pad,
+		paddingLeft:   pad,
+		paddingBottom: pad,
 		content:       d,
 	}
 }
@@ -52,10 +57,10 @@ func Padding4(pad Measure, d DrawStruct) DrawStruct {
 // PaddingLeftRight allows to adjust the padding on each side of the object border
 func Padding(l, t, r, b Measure, d DrawStruct) DrawStruct {
 	return padding{
-		paddingRight:  r.toPixel(),
-		paddingTop:    t.toPixel(),
-		paddingLeft:   l.toPixel(),
-		paddingBottom: b.toPixel(),
+		paddingRight:  r,
+		paddingTop:    t,
+		paddingLeft:   l,
+		paddingBottom: b,
 		content:       d,
 	}
 }
@@ -63,10 +68,10 @@ func Padding(l, t, r, b Measure, d DrawStruct) DrawStruct {
 // PaddingLeftRight adds left and right padding to the drawing object
 func PaddingLeftRight(pad Measure, d DrawStruct) DrawStruct {
 	return padding{
-		paddingRight:  pad.toPixel(),
-		paddingTop:    0,
-		paddingLeft:   pad.toPixel(),
-		paddingBottom: 0,
+		paddingRight:  pad,
+		paddingTop:    pixels(0),
+		paddingLeft:   pad,
+		paddingBottom: pixels(0),
 		content:       d,
 	}
 }
@@ -74,23 +79,17 @@ func PaddingLeftRight(pad Measure, d DrawStruct) DrawStruct {
 // PaddingLeftRight adds left and right padding to the drawing object
 func PaddingTopBottom(pad Measure, d DrawStruct) DrawStruct {
 	return padding{
-		paddingRight:  0,
-		paddingTop:    pad.toPixel(),
-		paddingLeft:   0,
-		paddingBottom: pad.toPixel(),
+		paddingRight:  pixels(0),
+		paddingTop:    pad,
+		paddingLeft:   pixels(0),
+		paddingBottom: pad,
 		content:       d,
 	}
 }
 
 func (p padding) getPaddingFnc() func(DrawStruct) DrawStruct {
 	return func(content DrawStruct) DrawStruct {
-		return Padding(
-			Pixels(p.paddingLeft),
-			Pixels(p.paddingTop),
-			Pixels(p.paddingRight),
-			Pixels(p.paddingBottom),
-			content,
-		)
+		return Padding(p.paddingLeft, p.paddingTop, p.paddingRight, p.paddingBottom, content)
 	}
 }
 
@@ -99,8 +98,25 @@ func (p padding) drawContent() DrawStruct {
 }
 
 func (p padding) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
-	result := p.content.WriteTo(canvas, padRect4(rect, p.paddingLeft, p.paddingTop, p.paddingRight, p.paddingBottom))
-	return image.Point{X: rect.Min.X, Y: result.Y + p.paddingBottom}
+	var (
+		bottom = canvas.toPixel(p.paddingBottom)
+		inner  = padRect4(rect, canvas.toPixel(p.paddingLeft), canvas.toPixel(p.paddingTop), canvas.toPixel(p.paddingRight), bottom)
+		result = p.content.WriteTo(canvas, inner)
+	)
+	return image.Point{X: rect.Min.X, Y: result.Y + bottom}
+}
+
+func (p padding) measureContent(m measurer) (minWidth, maxWidth int) {
+	var (
+		pad        = m.toWidth(p.paddingLeft) + m.toWidth(p.paddingRight)
+		cMin, cMax = measureStruct(m, p.content)
+	)
+	return cMin + pad, cMax + pad
+}
+
+func (p padding) defaultOptions(options ...TextOption) DrawStruct {
+	p.content = withDefaultOptions(p.content, options)
+	return p
 }
 
 func Empty() DrawStruct {
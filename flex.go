@@ -0,0 +1,436 @@
+package receipt
+
+import (
+	"image"
+)
+
+type (
+	// DrawFlex is the flexible container which can be customized with flex options
+	DrawFlex interface {
+		WriteTo(Canvas, image.Rectangle) image.Point
+		WithOptions(options ...FlexOption) DrawFlex
+	}
+	// FlexOption changes the layout of the Flex container:
+	//  OptionDirection, OptionWrap, OptionGap, OptionJustify, OptionAlignItems
+	FlexOption interface {
+		flexOptInt() int
+	}
+	// FlexDirection is the main axis of the Flex container:
+	//  FlexRow, FlexColumn
+	FlexDirection int
+	// Justify distributes the free space along the main axis:
+	//  JustifyStart, JustifyEnd, JustifyCenter, JustifySpaceBetween, JustifySpaceAround
+	Justify int
+	// AlignItems places the items along the cross axis:
+	//  AlignItemsStretch, AlignItemsStart, AlignItemsEnd, AlignItemsCenter
+	AlignItems int
+
+	flex struct {
+		items      []DrawStruct
+		direction  FlexDirection
+		wrap       bool
+		gap        Measure
+		justify    Justify
+		alignItems AlignItems
+	}
+	flexDirection struct {
+		direction FlexDirection
+	}
+	flexWrap struct{}
+	flexGap  struct {
+		gap Measure
+	}
+	flexJustify struct {
+		justify Justify
+	}
+	flexAlignItems struct {
+		align AlignItems
+	}
+	// SpacingOption is the option of both Flex and Grid containers
+	SpacingOption interface {
+		flexOptInt() int
+		gridOptInt() int
+	}
+	// layoutTarget draws the items of the containers on Canvas or TextCanvas, gaps are converted to the units of the target
+	layoutTarget struct {
+		m        measurer
+		gapX     int
+		gapY     int
+		toHeight func(Measure) int
+		write    func(d DrawStruct, rect image.Rectangle, measure bool) image.Point
+	}
+	flexItem struct {
+		draw    DrawStruct
+		size    Size
+		min     int
+		width   int
+		height  int
+		shrinks bool
+	}
+)
+
+const (
+	FlexRow FlexDirection = iota
+	FlexColumn
+)
+
+const (
+	JustifyStart Justify = iota
+	JustifyEnd
+	JustifyCenter
+	JustifySpaceBetween
+	JustifySpaceAround
+)
+
+const (
+	AlignItemsStretch AlignItems = iota
+	AlignItemsStart
+	AlignItemsEnd
+	AlignItemsCenter
+)
+
+// Flex lays the items out in a row (or in a column) and distributes the free space between them.
+// The items take the width of their content, use ColWidth to make an item fixed or growing (SizeFraction)
+//
+//	Flex(logo, Text("Corner Shop"), qr).WithOptions(OptionJustify(JustifySpaceBetween), OptionAlignItems(AlignItemsCenter))
+func Flex(items ...DrawStruct) DrawFlex {
+	return flex{
+		items: items,
+		gap:   pixels(0),
+	}
+}
+
+// OptionDirection sets the main axis of the container, FlexRow by default
+func OptionDirection(d FlexDirection) FlexOption {
+	return flexDirection{direction: d}
+}
+
+// OptionWrap moves the items which do not fit into the row to the next row
+func OptionWrap() FlexOption {
+	return flexWrap{}
+}
+
+// OptionGap sets the minimal space between the items and between the rows of Flex or Grid
+func OptionGap(gap Measure) SpacingOption {
+	return flexGap{gap: gap}
+}
+
+// OptionJustify sets how the free space of the main axis is distributed
+func OptionJustify(j Justify) FlexOption {
+	return flexJustify{justify: j}
+}
+
+// OptionAlignItems sets how the items are placed across the main axis
+func OptionAlignItems(a AlignItems) FlexOption {
+	return flexAlignItems{align: a}
+}
+
+func (_ flexDirection) flexOptInt() int {
+	return 0
+}
+
+func (_ flexWrap) flexOptInt() int {
+	return 0
+}
+
+func (_ flexGap) flexOptInt() int {
+	return 0
+}
+
+func (_ flexGap) gridOptInt() int {
+	return 0
+}
+
+func (_ flexJustify) flexOptInt() int {
+	return 0
+}
+
+func (_ flexAlignItems) flexOptInt() int {
+	return 0
+}
+
+func (f flex) WithOptions(options ...FlexOption) DrawFlex {
+	for _, opt := range options {
+		switch v := opt.(type) {
+		case flexDirection:
+			f.direction = v.direction
+		case flexWrap:
+			f.wrap = true
+		case flexGap:
+			f.gap = v.gap
+		case flexJustify:
+			f.justify = v.justify
+		case flexAlignItems:
+			f.alignItems = v.align
+		}
+	}
+	return f
+}
+
+func (f flex) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	return f.layout(canvasTarget(canvas, f.gap), rect)
+}
+
+func (f flex) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	return f.layout(textTarget(c, f.gap), rect)
+}
+
+func canvasTarget(canvas Canvas, gap Measure) layoutTarget {
+	return layoutTarget{
+		m:        canvas,
+		gapX:     canvas.toPixel(gap),
+		gapY:     canvas.toPixel(gap),
+		toHeight: canvas.toPixel,
+		write: func(d DrawStruct, rect image.Rectangle, measure bool) image.Point {
+			if measure {
+				return d.WriteTo(canvas.measure(), rect)
+			}
+			return d.WriteTo(canvas, rect)
+		},
+	}
+}
+
+func textTarget(c *TextCanvas, gap Measure) layoutTarget {
+	return layoutTarget{
+		m:        c,
+		gapX:     c.toColumns(gap),
+		gapY:     c.toLines(gap),
+		toHeight: c.toLines,
+		write: func(d DrawStruct, rect image.Rectangle, measure bool) image.Point {
+			if measure {
+				return c.scratch().writeStruct(d, rect)
+			}
+			return c.writeStruct(d, rect)
+		},
+	}
+}
+
+// height finds out the height of the object drawn into the width of the rectangle
+func (t layoutTarget) height(d DrawStruct, rect image.Rectangle) int {
+	return t.write(d, image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y), true).Y - rect.Min.Y
+}
+
+func (f flex) measureContent(m measurer) (minWidth, maxWidth int) {
+	gap := m.toWidth(f.gap)
+	for i, d := range f.items {
+		cMin, cMax := measureStruct(m, d)
+		if f.direction == FlexColumn {
+			minWidth, maxWidth = maxInt(minWidth, cMin), maxInt(maxWidth, cMax)
+			continue
+		}
+		if i > 0 {
+			maxWidth += gap
+		}
+		maxWidth += cMax
+		if f.wrap {
+			// every item can take the row of its own
+			minWidth = maxInt(minWidth, cMin)
+		} else {
+			if i > 0 {
+				minWidth += gap
+			}
+			minWidth += cMin
+		}
+	}
+	return minWidth, maxWidth
+}
+
+func (f flex) defaultOptions(options ...TextOption) DrawStruct {
+	items := make([]DrawStruct, len(f.items))
+	for i, d := range f.items {
+		items[i] = withDefaultOptions(d, options)
+	}
+	f.items = items
+	return f
+}
+
+func (f flex) layout(t layoutTarget, rect image.Rectangle) image.Point {
+	if len(f.items) == 0 {
+		return image.Point{X: rect.Max.X, Y: rect.Min.Y}
+	}
+	if f.direction == FlexColumn {
+		return f.layoutColumn(t, rect)
+	}
+	var (
+		items  = f.measureItems(t.m)
+		top    = rect.Min.Y
+		bottom = rect.Max.Y
+	)
+	for len(items) > 0 {
+		n := len(items)
+		if f.wrap {
+			n = flexLineLength(items, rect.Dx(), t.gapX)
+		}
+		line := items[:n]
+		items = items[n:]
+		lead, between := f.spacing(flexResize(line, rect.Dx()-t.gapX*(len(line)-1)), len(line), t.gapX)
+		// the measure pass finds the height of the line
+		var lineHeight int
+		for i := range line {
+			line[i].height = t.height(line[i].draw, image.Rect(rect.Min.X, top, rect.Min.X+line[i].width, top))
+			lineHeight = maxInt(lineHeight, line[i].height)
+		}
+		x := rect.Min.X + lead
+		for _, item := range line {
+			var (
+				y      = top + f.crossOffset(lineHeight-item.height)
+				height = item.height
+			)
+			if f.alignItems == AlignItemsStretch {
+				height = lineHeight
+			}
+			t.write(item.draw, image.Rect(x, y, x+item.width, y+height), false)
+			x += item.width + between
+		}
+		top += lineHeight
+		if len(items) > 0 {
+			top += t.gapY
+		}
+	}
+	return image.Point{X: rect.Max.X, Y: maxInt(top, bottom)}
+}
+
+// layoutColumn stacks the items, the free space is distributed if the height of the rectangle is known
+func (f flex) layoutColumn(t layoutTarget, rect image.Rectangle) image.Point {
+	var (
+		items = f.measureItems(t.m)
+		total = t.gapY * (len(items) - 1)
+	)
+	for i := range items {
+		switch {
+		case f.alignItems == AlignItemsStretch:
+			items[i].width = rect.Dx()
+		case items[i].width > rect.Dx():
+			items[i].width = rect.Dx()
+		}
+		items[i].height = t.height(items[i].draw, image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+items[i].width, rect.Min.Y))
+		total += items[i].height
+	}
+	var (
+		free          = maxInt(rect.Dy()-total, 0)
+		lead, between = f.spacing(free, len(items), t.gapY)
+		y             = rect.Min.Y + lead
+	)
+	for _, item := range items {
+		x := rect.Min.X + f.crossOffset(rect.Dx()-item.width)
+		t.write(item.draw, image.Rect(x, y, x+item.width, y+item.height), false)
+		y += item.height + between
+	}
+	return image.Point{X: rect.Max.X, Y: rect.Min.Y + total + free}
+}
+
+// measureItems finds the preferred widths of the items: the width of the content, the fixed width
+// or the width of the content which can grow for the fractions
+func (f flex) measureItems(m measurer) []flexItem {
+	items := make([]flexItem, len(f.items))
+	for i, d := range f.items {
+		items[i] = flexItem{draw: d, size: childSize(d)}
+		if fixed, ok := items[i].size.(sizeFixed); ok {
+			items[i].width = m.toWidth(fixed.width)
+			items[i].min = items[i].width
+			continue
+		}
+		items[i].min, items[i].width = measureStruct(m, d)
+		items[i].shrinks = true
+	}
+	return items
+}
+
+// flexLineLength returns how many of the items fit into the row, at least one
+func flexLineLength(items []flexItem, width, gap int) int {
+	used := items[0].width
+	for i := 1; i < len(items); i++ {
+		used += gap + items[i].width
+		if used > width {
+			return i
+		}
+	}
+	return len(items)
+}
+
+// flexResize shrinks the items down to their min-content widths if they do not fit,
+// or lets the fractions grow to take the free space. The free space which is left is returned
+func flexResize(items []flexItem, width int) int {
+	var (
+		used      int
+		shrinking int
+		sumPie    float64
+	)
+	for _, item := range items {
+		used += item.width
+		if item.shrinks {
+			shrinking += item.width - item.min
+		}
+		if s, ok := item.size.(sizeFraction); ok {
+			sumPie += s.pie
+		}
+	}
+	free := width - used
+	if free < 0 && shrinking > 0 {
+		excess := minInt(-free, shrinking)
+		for i := range items {
+			if items[i].shrinks {
+				items[i].width -= (items[i].width - items[i].min) * excess / shrinking
+			}
+		}
+		return 0
+	}
+	if free > 0 && sumPie > 0 {
+		var pie, taken float64
+		for i := range items {
+			if s, ok := items[i].size.(sizeFraction); ok {
+				pie += s.pie
+				grow := int(float64(free)*pie/sumPie - taken)
+				items[i].width += grow
+				taken += float64(grow)
+			}
+		}
+		return 0
+	}
+	return maxInt(free, 0)
+}
+
+// spacing returns the space before the first item and the space between the items
+func (f flex) spacing(free, count, gap int) (lead, between int) {
+	switch f.justify {
+	case JustifyEnd:
+		return free, gap
+	case JustifyCenter:
+		return free / 2, gap
+	case JustifySpaceBetween:
+		if count > 1 {
+			return 0, gap + free/(count-1)
+		}
+		return 0, gap
+	case JustifySpaceAround:
+		return free / (2 * count), gap + free/count
+	default:
+		return 0, gap
+	}
+}
+
+// crossOffset places the item across the main axis, free is the space the item does not take
+func (f flex) crossOffset(free int) int {
+	switch f.alignItems {
+	case AlignItemsEnd:
+		return free
+	case AlignItemsCenter:
+		return free / 2
+	default:
+		return 0
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
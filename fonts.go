@@ -0,0 +1,169 @@
+package receipt
+
+import (
+	"fmt"
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"sync"
+)
+
+type (
+	// faceCache keeps the faces of the fonts drawn on the canvas. The faces cache the glyphs and
+	// can not be used concurrently, so the fonts are shared and every canvas has its own faces
+	faceCache struct {
+		faces map[faceKey]font.Face
+	}
+	faceKey struct {
+		font    *truetype.Font
+		size    float64
+		dpi     float64
+		aliased bool
+	}
+	// aliasedFace draws the glyphs without anti-aliasing, the dot is either covered by the glyph or not
+	aliasedFace struct {
+		font.Face
+	}
+	// cachedFace remembers the metrics of the glyphs, the truetype face loads and hints the glyph to measure it
+	// every time, and the texts are measured many times by the layout
+	cachedFace struct {
+		font.Face
+		advances map[rune]glyphAdvance
+		bounds   map[rune]glyphBounds
+	}
+	glyphAdvance struct {
+		advance fixed.Int26_6
+		ok      bool
+	}
+	glyphBounds struct {
+		bounds  fixed.Rectangle26_6
+		advance fixed.Int26_6
+		ok      bool
+	}
+)
+
+// DefaultFontName is the name of the font the texts are drawn with unless OptionFont is set
+const DefaultFontName = "goregular"
+
+var (
+	fontsMx     sync.RWMutex
+	fonts       = map[string]*truetype.Font{}
+	defaultFont struct {
+		once sync.Once
+		font *truetype.Font
+	}
+)
+
+// RegisterFont parses the TrueType font and keeps it by the name, so the font is parsed once. The parsed fonts
+// are not changed by drawing and can be shared by the documents rendered concurrently
+//
+//	if err := RegisterFont("bold", gobold.TTF); err != nil {
+//		...
+//	}
+//	bold, _ := LoadFont("bold")
+//	Text("TOTAL", OptionFont(bold, 14, NewPen(color.Black, Pixels(1))))
+func RegisterFont(name string, ttf []byte) error {
+	f, err := freetype.ParseFont(ttf)
+	if err != nil {
+		return fmt.Errorf("cannot parse font %q: %w", name, err)
+	}
+	fontsMx.Lock()
+	fonts[name] = f
+	fontsMx.Unlock()
+	return nil
+}
+
+// LoadFont returns the font registered by the name, DefaultFontName is always registered
+func LoadFont(name string) (*truetype.Font, bool) {
+	if name == DefaultFontName {
+		return getDefaultFont(), true
+	}
+	fontsMx.RLock()
+	f, ok := fonts[name]
+	fontsMx.RUnlock()
+	return f, ok
+}
+
+func newFaceCache() *faceCache {
+	return &faceCache{faces: make(map[faceKey]font.Face)}
+}
+
+// face returns the face of the font of the size at the resolution, the canvas without the cache gets the new face
+func (c *faceCache) face(f *truetype.Font, size, dpi float64, aliased bool) font.Face {
+	if c == nil {
+		return newFace(f, size, dpi, aliased)
+	}
+	key := faceKey{font: f, size: size, dpi: dpi, aliased: aliased}
+	face, ok := c.faces[key]
+	if !ok {
+		face = &cachedFace{
+			Face:     newFace(f, size, dpi, aliased),
+			advances: make(map[rune]glyphAdvance),
+			bounds:   make(map[rune]glyphBounds),
+		}
+		c.faces[key] = face
+	}
+	return face
+}
+
+func newFace(f *truetype.Font, size, dpi float64, aliased bool) font.Face {
+	face := truetype.NewFace(f, &truetype.Options{
+		Size:    size,
+		Hinting: font.HintingFull,
+		DPI:     dpi,
+	})
+	if aliased {
+		return aliasedFace{Face: face}
+	}
+	return face
+}
+
+func (f aliasedFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	dr, mask, maskp, advance, ok := f.Face.Glyph(dot, r)
+	if !ok {
+		return dr, mask, maskp, advance, ok
+	}
+	// the mask of the face is reused by the next glyph, so the dots are copied
+	aliased := image.NewAlpha(image.Rectangle{Min: maskp, Max: maskp.Add(dr.Size())})
+	for y := aliased.Rect.Min.Y; y < aliased.Rect.Max.Y; y++ {
+		for x := aliased.Rect.Min.X; x < aliased.Rect.Max.X; x++ {
+			if _, _, _, a := mask.At(x, y).RGBA(); a >= 0x8000 {
+				aliased.Pix[aliased.PixOffset(x, y)] = 0xff
+			}
+		}
+	}
+	return dr, aliased, maskp, advance, ok
+}
+
+func (f *cachedFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	a, ok := f.advances[r]
+	if !ok {
+		a.advance, a.ok = f.Face.GlyphAdvance(r)
+		f.advances[r] = a
+	}
+	return a.advance, a.ok
+}
+
+func (f *cachedFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	b, ok := f.bounds[r]
+	if !ok {
+		b.bounds, b.advance, b.ok = f.Face.GlyphBounds(r)
+		f.bounds[r] = b
+	}
+	return b.bounds, b.advance, b.ok
+}
+
+// getDefaultFont returns the Go Regular font, it is parsed the first time it is needed
+func getDefaultFont() *truetype.Font {
+	defaultFont.once.Do(func() {
+		f, err := freetype.ParseFont(goregular.TTF)
+		if err != nil {
+			panic(err)
+		}
+		defaultFont.font = f
+	})
+	return defaultFont.font
+}
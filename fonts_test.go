@@ -0,0 +1,63 @@
+package receipt
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+// inkHeight returns the number of the rows of the image which have any ink
+func inkHeight(img image.Image) int {
+	var rows int
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a > 0x8000 {
+				rows++
+				break
+			}
+		}
+	}
+	return rows
+}
+
+func TestTextScalesWithDPI(t *testing.T) {
+	var (
+		heights = make(map[float64]int)
+		ink     = make(map[float64]int)
+	)
+	for _, dpi := range []float64{203, 300} {
+		canvas := testCanvas(1000, 400)
+		canvas.SetDPI(dpi)
+		heights[dpi] = canvas.Write(Text("HHHH")).Y
+		ink[dpi] = inkHeight(canvas.img)
+	}
+	if ink[203] == 0 {
+		t.Fatal("the text is not drawn")
+	}
+	const scale = 300.0 / 203
+	for name, got := range map[string][2]int{"line": {heights[203], heights[300]}, "glyph": {ink[203], ink[300]}} {
+		if want := float64(got[0]) * scale; math.Abs(float64(got[1])-want) > 2 {
+			t.Errorf("the %s is %d pixels high at 203 dpi and %d at 300 dpi, about %.0f expected", name, got[0], got[1], want)
+		}
+	}
+}
+
+func TestFaceCacheReused(t *testing.T) {
+	canvas := testCanvas(1000, 400)
+	canvas.SetDPI(203)
+	canvas.Write(Lines(Text("first"), Text("second")))
+	if n := len(canvas.faces.faces); n != 1 {
+		t.Fatalf("%d faces are made for one font, 1 expected", n)
+	}
+	face := canvas.faces.face(getDefaultFont(), defaultFontSize, 203, false)
+	canvas.Write(Text("third"))
+	if canvas.faces.face(getDefaultFont(), defaultFontSize, 203, false) != face {
+		t.Error("the face of the same font, size and resolution is made again")
+	}
+	canvas.SetDPI(300)
+	canvas.Write(Text("fourth"))
+	if n := len(canvas.faces.faces); n != 2 {
+		t.Errorf("%d faces are made for two resolutions, 2 expected", n)
+	}
+}
@@ -0,0 +1,411 @@
+package receipt
+
+import (
+	"image"
+	"strings"
+)
+
+type (
+	// DrawGrid is the grid container which can be customized with grid options
+	DrawGrid interface {
+		WriteTo(Canvas, image.Rectangle) image.Point
+		WithOptions(options ...GridOption) DrawGrid
+	}
+	// GridOption changes the layout of the Grid container:
+	//  OptionGap, OptionAreas
+	GridOption interface {
+		gridOptInt() int
+	}
+
+	grid struct {
+		columns []Size
+		rows    []Size
+		items   []DrawStruct
+		gap     Measure
+		areas   []string
+	}
+	gridAreas struct {
+		rows []string
+	}
+	gridAt struct {
+		col  int
+		row  int
+		draw DrawStruct
+	}
+	gridArea struct {
+		name string
+		draw DrawStruct
+	}
+	trackSpan struct {
+		start int
+		end   int
+	}
+	gridItem struct {
+		draw    DrawStruct
+		col     int
+		row     int
+		span    int
+		rowSpan int
+	}
+)
+
+// Grid places the items into the cells of the grid. The columns and the rows are tracks:
+// SizeFixed, SizeFraction or SizeAuto (the size of the content). Fraction rows share the height
+// of the rectangle if it is known and behave like auto rows otherwise. The items are placed by GridAt or GridArea,
+// the rest of them fill the free cells row by row; wrap the item into ColSpan or RowSpan to span several cells.
+// The rows which are not described are auto-sized
+//
+//	Grid([]Size{SizeAuto(), SizeFraction(1)}, nil, RowSpan(qr, 2), Text("Gift card"), Text("100.00"))
+func Grid(columns, rows []Size, items ...DrawStruct) DrawGrid {
+	return grid{
+		columns: columns,
+		rows:    rows,
+		items:   items,
+		gap:     pixels(0),
+	}
+}
+
+// GridAt places the item into the cell with the column and row numbers, starting from zero
+func GridAt(col, row int, d DrawStruct) DrawStruct {
+	return gridAt{
+		col:  col,
+		row:  row,
+		draw: d,
+	}
+}
+
+// GridArea places the item into the area named by OptionAreas
+func GridArea(name string, d DrawStruct) DrawStruct {
+	return gridArea{
+		name: name,
+		draw: d,
+	}
+}
+
+// OptionAreas names the cells of the grid, one string per row with the names of the cells separated by spaces.
+// The cells with the same name make the area, which must be rectangular, e.g.
+//
+//	OptionAreas("logo title", "logo amount", "code code")
+func OptionAreas(rows ...string) GridOption {
+	return gridAreas{rows: rows}
+}
+
+func (_ gridAreas) gridOptInt() int {
+	return 0
+}
+
+func (g gridAt) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	return g.draw.WriteTo(canvas, rect)
+}
+
+func (g gridArea) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	return g.draw.WriteTo(canvas, rect)
+}
+
+func (g grid) WithOptions(options ...GridOption) DrawGrid {
+	for _, opt := range options {
+		switch v := opt.(type) {
+		case flexGap:
+			g.gap = v.gap
+		case gridAreas:
+			g.areas = append([]string{}, v.rows...)
+		}
+	}
+	return g
+}
+
+func (g grid) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	return g.layout(canvasTarget(canvas, g.gap), rect)
+}
+
+func (g grid) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	return g.layout(textTarget(c, g.gap), rect)
+}
+
+func (g grid) measureContent(m measurer) (minWidth, maxWidth int) {
+	var (
+		items  = g.placeItems()
+		tracks = g.columnTracks(m, items)
+		gap    int
+	)
+	if len(tracks) > 1 {
+		gap = m.toWidth(g.gap) * (len(tracks) - 1)
+	}
+	for _, t := range tracks {
+		minWidth += t.minContent
+		maxWidth += t.maxContent
+	}
+	return minWidth + gap, maxWidth + gap
+}
+
+func (g grid) defaultOptions(options ...TextOption) DrawStruct {
+	items := make([]DrawStruct, len(g.items))
+	for i, d := range g.items {
+		items[i] = withDefaultOptions(d, options)
+	}
+	g.items = items
+	return g
+}
+
+func (g gridAt) defaultOptions(options ...TextOption) DrawStruct {
+	g.draw = withDefaultOptions(g.draw, options)
+	return g
+}
+
+func (g gridArea) defaultOptions(options ...TextOption) DrawStruct {
+	g.draw = withDefaultOptions(g.draw, options)
+	return g
+}
+
+func (g grid) layout(t layoutTarget, rect image.Rectangle) image.Point {
+	if len(g.columns) == 0 {
+		return image.Point{X: rect.Max.X, Y: rect.Min.Y}
+	}
+	var (
+		items   = g.placeItems()
+		widths  = distributeSizes(rect.Dx()-t.gapX*(len(g.columns)-1), g.columnTracks(t.m, items))
+		columns = trackBounds(rect.Min.X, widths, t.gapX)
+		heights = g.rowHeights(t, items, rect, columns)
+		rows    = trackBounds(rect.Min.Y, heights, t.gapY)
+	)
+	for _, item := range items {
+		t.write(item.draw, image.Rect(
+			columns[item.col].start,
+			rows[item.row].start,
+			columns[item.col+item.span-1].end,
+			rows[item.row+item.rowSpan-1].end,
+		), false)
+	}
+	bottom := rect.Min.Y
+	if len(rows) > 0 {
+		bottom = rows[len(rows)-1].end
+	}
+	return image.Point{X: rect.Max.X, Y: bottom}
+}
+
+// trackBounds returns the start and the end of each track
+func trackBounds(start int, sizes []int, gap int) []trackSpan {
+	bounds := make([]trackSpan, len(sizes))
+	for i, size := range sizes {
+		bounds[i] = trackSpan{start: start, end: start + size}
+		start += size + gap
+	}
+	return bounds
+}
+
+// placeItems finds the cells of the items: GridAt and GridArea items take their cells first,
+// the other items fill the free cells row by row in the order they are listed
+func (g grid) placeItems() []gridItem {
+	var (
+		columns  = len(g.columns)
+		items    = make([]gridItem, 0, len(g.items))
+		occupied = make(map[image.Point]bool)
+		auto     []gridItem
+		occupy   = func(item gridItem) {
+			for r := item.row; r < item.row+item.rowSpan; r++ {
+				for c := item.col; c < item.col+item.span; c++ {
+					occupied[image.Point{X: c, Y: r}] = true
+				}
+			}
+		}
+		fits = func(item gridItem) bool {
+			for r := item.row; r < item.row+item.rowSpan; r++ {
+				for c := item.col; c < item.col+item.span; c++ {
+					if occupied[image.Point{X: c, Y: r}] {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		areas = g.areaCells()
+	)
+	if columns == 0 {
+		return nil
+	}
+	for _, d := range g.items {
+		item := unwrapGridItem(d)
+		switch v := d.(type) {
+		case gridAt:
+			item.col, item.row = v.col, v.row
+		case gridArea:
+			area, ok := areas[v.name]
+			if !ok {
+				// the item of the unknown area is placed like the items without a place
+				auto = append(auto, item)
+				continue
+			}
+			item.col, item.row, item.span, item.rowSpan = area.Min.X, area.Min.Y, area.Dx(), area.Dy()
+		default:
+			auto = append(auto, item)
+			continue
+		}
+		item.col = clamp(item.col, 0, columns-1)
+		item.span = clamp(item.span, 1, columns-item.col)
+		if item.row < 0 {
+			item.row = 0
+		}
+		occupy(item)
+		items = append(items, item)
+	}
+	var col, row int
+	for _, item := range auto {
+		item.span = clamp(item.span, 1, columns)
+		for {
+			if col+item.span > columns {
+				col, row = 0, row+1
+				continue
+			}
+			item.col, item.row = col, row
+			if fits(item) {
+				break
+			}
+			col++
+		}
+		occupy(item)
+		items = append(items, item)
+		col += item.span
+	}
+	return items
+}
+
+// unwrapGridItem finds the spans of the item
+func unwrapGridItem(d DrawStruct) gridItem {
+	item := gridItem{draw: d, span: 1, rowSpan: 1}
+	switch v := d.(type) {
+	case gridAt:
+		d = v.draw
+	case gridArea:
+		d = v.draw
+	}
+	for {
+		switch v := d.(type) {
+		case ColumnSpan:
+			item.span = v.spanCount()
+			d = v.drawContent()
+		case RowSpanning:
+			item.rowSpan = v.rowSpanCount()
+			d = v.drawContent()
+		default:
+			if item.rowSpan < 1 {
+				item.rowSpan = 1
+			}
+			item.draw = d
+			return item
+		}
+	}
+}
+
+// areaCells finds the bounds of the named areas in the units of cells
+func (g grid) areaCells() map[string]image.Rectangle {
+	areas := make(map[string]image.Rectangle)
+	for row, names := range g.areas {
+		for col, name := range strings.Fields(names) {
+			cell := image.Rect(col, row, col+1, row+1)
+			if area, ok := areas[name]; ok {
+				cell = area.Union(cell)
+			}
+			areas[name] = cell
+		}
+	}
+	return areas
+}
+
+// columnTracks measures the auto-sized columns by the items which do not span several columns
+func (g grid) columnTracks(m measurer, items []gridItem) []sizeTrack {
+	tracks := make([]sizeTrack, len(g.columns))
+	for i, size := range g.columns {
+		tracks[i].size = size
+		if f, ok := size.(sizeFixed); ok {
+			tracks[i].minContent = m.toWidth(f.width)
+			tracks[i].maxContent = tracks[i].minContent
+		}
+	}
+	for _, item := range items {
+		if _, ok := tracks[item.col].size.(sizeAuto); !ok || item.span > 1 {
+			continue
+		}
+		minWidth, maxWidth := measureStruct(m, item.draw)
+		tracks[item.col].minContent = maxInt(tracks[item.col].minContent, minWidth)
+		tracks[item.col].maxContent = maxInt(tracks[item.col].maxContent, maxWidth)
+	}
+	return tracks
+}
+
+// rowHeights measures the items in the widths of their columns. The items spanning several rows
+// make the last of the rows higher if they do not fit. Fraction rows share the rest of the height of the rectangle
+func (g grid) rowHeights(t layoutTarget, items []gridItem, rect image.Rectangle, columns []trackSpan) []int {
+	var count = len(g.rows)
+	for _, item := range items {
+		count = maxInt(count, item.row+item.rowSpan)
+	}
+	var (
+		heights = make([]int, count)
+		sizes   = make([]Size, count)
+		sumPie  float64
+	)
+	for i := range sizes {
+		sizes[i] = sizeAuto{}
+		if i < len(g.rows) {
+			sizes[i] = g.rows[i]
+		}
+		switch s := sizes[i].(type) {
+		case sizeFixed:
+			heights[i] = t.toHeight(s.width)
+		case sizeFraction:
+			sumPie += s.pie
+		}
+	}
+	itemHeight := func(item gridItem) int {
+		return t.height(item.draw, image.Rect(columns[item.col].start, rect.Min.Y, columns[item.col+item.span-1].end, rect.Min.Y))
+	}
+	for _, item := range items {
+		if _, ok := sizes[item.row].(sizeFixed); ok || item.rowSpan > 1 {
+			continue
+		}
+		heights[item.row] = maxInt(heights[item.row], itemHeight(item))
+	}
+	if sumPie > 0 {
+		used := t.gapY * (count - 1)
+		for i, size := range sizes {
+			if _, ok := size.(sizeFraction); !ok {
+				used += heights[i]
+			}
+		}
+		if free := rect.Dy() - used; free > 0 {
+			var pie, taken float64
+			for i, size := range sizes {
+				if s, ok := size.(sizeFraction); ok {
+					pie += s.pie
+					share := int(float64(free)*pie/sumPie - taken)
+					heights[i] = maxInt(heights[i], share)
+					taken += float64(share)
+				}
+			}
+		}
+	}
+	for _, item := range items {
+		if item.rowSpan < 2 {
+			continue
+		}
+		last := item.row + item.rowSpan - 1
+		spanned := t.gapY * (item.rowSpan - 1)
+		for r := item.row; r <= last; r++ {
+			spanned += heights[r]
+		}
+		if h := itemHeight(item); h > spanned {
+			heights[last] += h - spanned
+		}
+	}
+	return heights
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,11 @@
+package receipt
+
+import "testing"
+
+func TestGridWithoutColumns(t *testing.T) {
+	g := Grid(nil, nil, Text("lost")).WithOptions(OptionGap(Millimeters(2)))
+	minWidth, maxWidth := g.(grid).measureContent(testCanvas(100, 100))
+	if minWidth != 0 || maxWidth != 0 {
+		t.Errorf("the grid without columns is %d..%d pixels wide, 0 expected", minWidth, maxWidth)
+	}
+}
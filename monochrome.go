@@ -0,0 +1,278 @@
+package receipt
+
+import (
+	"image"
+	"image/color"
+)
+
+type (
+	// Monochrome is the packed 1-bit image, the raster format of ESC/POS and most thermal printers: every byte keeps
+	// eight dots of the row, the most significant bit is the leftmost dot and the set bit is the black dot.
+	// The rows are padded to whole bytes
+	Monochrome struct {
+		Pix    []uint8
+		Stride int
+		Rect   image.Rectangle
+	}
+	// Dithering is the way the shades of grey are turned into black and white dots:
+	//  DitherNone, DitherFloydSteinberg, DitherBayer
+	Dithering int
+	// MonochromeOption changes the conversion of the image into Monochrome:
+	//  OptionThreshold, OptionDithering
+	MonochromeOption interface {
+		monochromeOptInt() int
+	}
+
+	monochromeThreshold struct {
+		level uint8
+	}
+	monochromeDithering struct {
+		dithering Dithering
+	}
+	// monochromeSettings are the threshold and the dithering of the conversion, the canvas which has them
+	// draws for the monochrome output (see Canvas.SetMonochrome)
+	monochromeSettings struct {
+		threshold int
+		dithering Dithering
+	}
+)
+
+const (
+	// DitherNone makes the dots darker than the threshold black, it keeps the glyphs of the text sharp
+	DitherNone Dithering = iota
+	// DitherFloydSteinberg spreads the error of every dot to its neighbours, it suits photos and logos with gradients
+	DitherFloydSteinberg
+	// DitherBayer compares the dots with the ordered 8x8 pattern, the flat shades become regular dot patterns
+	DitherBayer
+)
+
+// monochromePalette is the color model of Monochrome
+var monochromePalette = color.Palette{color.White, color.Black}
+
+var bayerMatrix = [8][8]int{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// NewMonochrome creates the white image of the rectangle
+func NewMonochrome(r image.Rectangle) *Monochrome {
+	stride := (r.Dx() + 7) / 8
+	return &Monochrome{
+		Pix:    make([]uint8, stride*r.Dy()),
+		Stride: stride,
+		Rect:   r,
+	}
+}
+
+// OptionThreshold sets the level of grey (0 is black, 255 is white) below which the dots are black, 128 by default.
+// The lower threshold makes the print lighter
+func OptionThreshold(level uint8) MonochromeOption {
+	return monochromeThreshold{level: level}
+}
+
+// OptionDithering sets the way the shades of grey are printed, DitherNone by default
+func OptionDithering(d Dithering) MonochromeOption {
+	return monochromeDithering{dithering: d}
+}
+
+func (_ monochromeThreshold) monochromeOptInt() int {
+	return 0
+}
+
+func (_ monochromeDithering) monochromeOptInt() int {
+	return 0
+}
+
+// ToMonochrome converts the rendered image into black and white dots, the transparent parts are white (as the paper is).
+// The dithering is applied to the whole image, the text included; the canvas prepared by SetMonochrome draws
+// the text without grey edges and dithers the pictures only, so its image needs the threshold only
+//
+//	mono := ToMonochrome(img, OptionDithering(DitherFloydSteinberg))
+//	printer.PrintRaster(mono.Pix, mono.Stride, mono.Rect.Dy())
+func ToMonochrome(img image.Image, options ...MonochromeOption) *Monochrome {
+	return newMonochromeSettings(options).convert(img)
+}
+
+// SetMonochrome prepares the canvas for the output to the thermal printer: the glyphs, the rules, the boxes and
+// the shapes are drawn without anti-aliasing (the dot is either covered by the object or not), and the pictures
+// are dithered with the options as they are drawn, so the text stays sharp whatever the dithering is
+//
+//	canvas.SetMonochrome(OptionDithering(DitherFloydSteinberg))
+//	canvas.Write(Lines(Picture(logo, Millimeters(40)), receipt))
+//	mono := ToMonochrome(img)
+func (c *Canvas) SetMonochrome(options ...MonochromeOption) {
+	s := newMonochromeSettings(options)
+	c.mono = &s
+}
+
+func newMonochromeSettings(options []MonochromeOption) monochromeSettings {
+	s := monochromeSettings{threshold: 128, dithering: DitherNone}
+	for _, opt := range options {
+		switch v := opt.(type) {
+		case monochromeThreshold:
+			s.threshold = int(v.level)
+		case monochromeDithering:
+			s.dithering = v.dithering
+		}
+	}
+	return s
+}
+
+func (s monochromeSettings) convert(img image.Image) *Monochrome {
+	var (
+		threshold = s.threshold
+		bounds    = img.Bounds()
+		gray      = grayReader(img)
+		m         = NewMonochrome(bounds)
+	)
+	switch s.dithering {
+	case DitherFloydSteinberg:
+		m.floydSteinberg(gray, threshold)
+	case DitherBayer:
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				// the pattern spreads the levels around the threshold
+				level := threshold + (bayerMatrix[y&7][x&7]*256/64 + 2 - 128)
+				m.setBlack(x, y, gray(x, y) < level)
+			}
+		}
+	default:
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				m.setBlack(x, y, gray(x, y) < threshold)
+			}
+		}
+	}
+	return m
+}
+
+// dither turns the picture into the black dots and the transparent ones, which leave the paper as it is
+func (s monochromeSettings) dither(img *image.RGBA) {
+	m := s.convert(img)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := color.RGBA{}
+			if m.Black(x, y) {
+				c = color.RGBA{A: 0xff}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// aliasMask makes every dot of the mask either covered or not, the dots covered by half or more are covered
+func aliasMask(mask *image.Alpha) {
+	for i, a := range mask.Pix {
+		if a >= 0x80 {
+			mask.Pix[i] = 0xff
+		} else {
+			mask.Pix[i] = 0
+		}
+	}
+}
+
+// floydSteinberg passes 7/16 of the error of the dot to the right, 3/16, 5/16 and 1/16 to the dots below
+func (m *Monochrome) floydSteinberg(gray func(x, y int) int, threshold int) {
+	var (
+		bounds = m.Rect
+		width  = bounds.Dx()
+		// the errors of the current and of the next row, with the room for the neighbours beyond the edges
+		current = make([]int, width+2)
+		next    = make([]int, width+2)
+	)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for i := 0; i < width; i++ {
+			var (
+				x     = bounds.Min.X + i
+				level = gray(x, y) + current[i+1]/16
+				black = level < threshold
+				err   = level
+			)
+			if !black {
+				err = level - 255
+			}
+			m.setBlack(x, y, black)
+			current[i+2] += err * 7
+			next[i] += err * 3
+			next[i+1] += err * 5
+			next[i+2] += err
+		}
+		current, next = next, current
+		for i := range next {
+			next[i] = 0
+		}
+	}
+}
+
+// grayReader returns the function which reads the brightness of the dots, *image.RGBA is read directly
+func grayReader(img image.Image) func(x, y int) int {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return func(x, y int) int {
+			s := rgba.Pix[rgba.PixOffset(x, y):]
+			return grayLevel(uint32(s[0])*0x101, uint32(s[1])*0x101, uint32(s[2])*0x101, uint32(s[3])*0x101)
+		}
+	}
+	return func(x, y int) int {
+		return grayLevel(img.At(x, y).RGBA())
+	}
+}
+
+// grayLevel returns the brightness of the color from 0 (black) to 255 (white) on the white paper
+func grayLevel(r, g, b, a uint32) int {
+	// the weights are the ones of color.GrayModel, the colors are premultiplied by alpha
+	y := (19595*r + 38470*g + 7471*b + 1<<15) >> 16
+	return int((y + 0xffff - a) >> 8)
+}
+
+func (m *Monochrome) ColorModel() color.Model {
+	return monochromePalette
+}
+
+func (m *Monochrome) Bounds() image.Rectangle {
+	return m.Rect
+}
+
+func (m *Monochrome) At(x, y int) color.Color {
+	if m.Black(x, y) {
+		return color.Black
+	}
+	return color.White
+}
+
+// Set makes the dot black if the color is darker than the middle grey, Monochrome can be the image of the Canvas
+func (m *Monochrome) Set(x, y int, c color.Color) {
+	m.setBlack(x, y, grayLevel(c.RGBA()) < 128)
+}
+
+// Black reports whether the dot is black
+func (m *Monochrome) Black(x, y int) bool {
+	if !(image.Point{X: x, Y: y}.In(m.Rect)) {
+		return false
+	}
+	i, bit := m.bitOffset(x, y)
+	return m.Pix[i]&bit != 0
+}
+
+func (m *Monochrome) setBlack(x, y int, black bool) {
+	if !(image.Point{X: x, Y: y}.In(m.Rect)) {
+		return
+	}
+	i, bit := m.bitOffset(x, y)
+	if black {
+		m.Pix[i] |= bit
+	} else {
+		m.Pix[i] &^= bit
+	}
+}
+
+// bitOffset returns the index of the byte and the mask of the bit of the dot
+func (m *Monochrome) bitOffset(x, y int) (int, uint8) {
+	x -= m.Rect.Min.X
+	return (y-m.Rect.Min.Y)*m.Stride + x/8, 0x80 >> uint(x%8)
+}
@@ -0,0 +1,83 @@
+package receipt
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// greyDots counts the dots of the region which are neither transparent nor opaque
+func greyDots(img *image.RGBA, r image.Rectangle) (grey, opaque int) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			switch img.RGBAAt(x, y).A {
+			case 0:
+			case 0xff:
+				opaque++
+			default:
+				grey++
+			}
+		}
+	}
+	return grey, opaque
+}
+
+func TestMonochromeAliased(t *testing.T) {
+	document := func() DrawStruct {
+		pen := NewPen(color.Black, Millimeters(0.3)).Antialiased()
+		return Lines(
+			Text("Receipt 42"),
+			HRule(pen, Pixels(0), LineDashed),
+			Box(Text("coupon")).WithOptions(OptionBoxBorder(BorderAll, pen), OptionRadius(Millimeters(2))),
+			Rotate(30, Text("void")),
+			Ellipse(Millimeters(4), Millimeters(3)),
+		)
+	}
+	for _, mono := range []bool{false, true} {
+		canvas := testCanvas(600, 600)
+		canvas.SetDPI(203)
+		if mono {
+			canvas.SetMonochrome()
+		}
+		canvas.Write(document())
+		grey, opaque := greyDots(canvas.img.(*image.RGBA), canvas.rect)
+		if opaque == 0 {
+			t.Fatal("nothing is drawn")
+		}
+		if mono && grey > 0 {
+			t.Errorf("%d grey dots are drawn on the monochrome canvas", grey)
+		}
+		if !mono && grey == 0 {
+			t.Error("the anti-aliased canvas has no grey dots")
+		}
+	}
+}
+
+func TestMonochromePicture(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.Gray{Y: 128})
+		}
+	}
+	for _, dithering := range []Dithering{DitherBayer, DitherFloydSteinberg} {
+		canvas := testCanvas(200, 200)
+		canvas.SetMonochrome(OptionDithering(dithering))
+		end := canvas.Write(Lines(Picture(img, Pixels(40)), Text("Total")))
+		var (
+			picture        = image.Rect(0, 0, 40, 40)
+			grey, opaque   = greyDots(canvas.img.(*image.RGBA), picture)
+			textGrey, text = greyDots(canvas.img.(*image.RGBA), image.Rect(0, 40, 200, end.Y))
+		)
+		if grey > 0 {
+			t.Errorf("dithering %d: %d grey dots in the picture", dithering, grey)
+		}
+		// the middle grey is printed with about a half of the dots black
+		if opaque < 600 || opaque > 1000 {
+			t.Errorf("dithering %d: %d dots of 1600 are black, about 800 expected", dithering, opaque)
+		}
+		if textGrey > 0 || text == 0 {
+			t.Errorf("dithering %d: the text has %d grey and %d black dots", dithering, textGrey, text)
+		}
+	}
+}
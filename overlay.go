@@ -0,0 +1,161 @@
+package receipt
+
+import (
+	"image"
+	"sort"
+)
+
+type (
+	// Anchor is the corner (or the center) of the page the absolute position is measured from:
+	//  AnchorTopLeft, AnchorTopRight, AnchorBottomLeft, AnchorBottomRight, AnchorCenter
+	Anchor int
+
+	absolute struct {
+		anchor Anchor
+		x      Measure
+		y      Measure
+		width  Measure
+		draw   DrawStruct
+	}
+	overlay struct {
+		z    int
+		draw DrawStruct
+	}
+	// layers keeps the overlays until they are flushed, it is shared by the copies of the canvas
+	layers struct {
+		overlays []overlay
+		// the roll does not know its height until it is closed, so the objects placed from the bottom
+		// or from the center of the page wait in anchored, see RollCanvas
+		deferAnchored bool
+		anchored      []absolute
+	}
+)
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomRight
+	AnchorCenter
+)
+
+// Absolute draws the object at the position on the page which does not depend on the flow of the document:
+// x and y are the distances from the anchor to the nearest sides of the object (the offsets from the center
+// for AnchorCenter). The object is width wide, zero width means the width of its content.
+// Absolute takes no space in the flow, so it can be written to the canvas or put among Lines anywhere
+//
+//	Absolute(AnchorBottomLeft, Millimeters(10), Millimeters(15), Millimeters(60), Text("Signature ____________"))
+func Absolute(anchor Anchor, x, y, width Measure, d DrawStruct) DrawStruct {
+	return absolute{
+		anchor: anchor,
+		x:      x,
+		y:      y,
+		width:  width,
+		draw:   d,
+	}
+}
+
+func (a absolute) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	if canvas.layers != nil && canvas.layers.deferAnchored && a.anchor != AnchorTopLeft && a.anchor != AnchorTopRight {
+		if _, ok := canvas.img.(nullImage); !ok {
+			canvas.layers.anchored = append(canvas.layers.anchored, a)
+		}
+		return rect.Min
+	}
+	t := canvasTarget(canvas, pixels(0))
+	t.write(a.draw, a.place(t, canvas.rect), false)
+	return rect.Min
+}
+
+// writeGrid places the object on the text page, the bottom of the page is the last line written so far
+func (a absolute) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	t := textTarget(c, pixels(0))
+	t.write(a.draw, a.place(t, image.Rect(0, 0, c.columns, c.lines)), false)
+	return rect.Min
+}
+
+// place finds the rectangle of the object on the page
+func (a absolute) place(t layoutTarget, page image.Rectangle) image.Rectangle {
+	var (
+		x      = t.m.toWidth(a.x)
+		y      = t.toHeight(a.y)
+		width  = t.m.toWidth(a.width)
+		left   int
+		top    int
+		height int
+	)
+	if width <= 0 {
+		_, width = measureStruct(t.m, a.draw)
+	}
+	if width <= 0 || width > page.Dx() {
+		width = page.Dx()
+	}
+	switch a.anchor {
+	case AnchorTopRight, AnchorBottomRight:
+		left = page.Max.X - x - width
+	case AnchorCenter:
+		left = page.Min.X + (page.Dx()-width)/2 + x
+	default:
+		left = page.Min.X + x
+	}
+	if a.anchor != AnchorTopLeft && a.anchor != AnchorTopRight {
+		// the height is needed to measure the position from the bottom or from the center
+		height = t.height(a.draw, image.Rect(left, page.Min.Y, left+width, page.Min.Y))
+	}
+	switch a.anchor {
+	case AnchorBottomLeft, AnchorBottomRight:
+		top = page.Max.Y - y - height
+	case AnchorCenter:
+		top = page.Min.Y + (page.Dy()-height)/2 + y
+	default:
+		top = page.Min.Y + y
+	}
+	return image.Rect(left, top, left+width, top+height)
+}
+
+// Overlay keeps the object to draw it over the flowed content when Flush is called. The overlays are drawn
+// into the whole canvas in the order of z (the higher z is drawn over the lower one), use Absolute to place them
+//
+//	canvas.Overlay(1, Absolute(AnchorTopRight, Millimeters(5), Millimeters(5), Millimeters(30), Text("PAID")))
+func (c *Canvas) Overlay(z int, d DrawStruct) {
+	if c.layers == nil {
+		c.layers = &layers{}
+	}
+	c.layers.add(z, d)
+}
+
+// Flush draws the overlays, the flow position of the canvas is not changed
+func (c *Canvas) Flush() {
+	if c.layers == nil {
+		return
+	}
+	for _, o := range c.layers.flush() {
+		o.draw.WriteTo(*c, c.rect)
+	}
+}
+
+// Overlay keeps the object to write it over the text when Flush is called, see Canvas.Overlay
+func (c *TextCanvas) Overlay(z int, d DrawStruct) {
+	c.layers.add(z, d)
+}
+
+// Flush writes the overlays over the text written so far
+func (c *TextCanvas) Flush() {
+	for _, o := range c.layers.flush() {
+		c.writeStruct(o.draw, image.Rect(0, 0, c.columns, c.lines))
+	}
+}
+
+func (l *layers) add(z int, d DrawStruct) {
+	l.overlays = append(l.overlays, overlay{z: z, draw: d})
+}
+
+// flush returns the overlays in the order of drawing and forgets them
+func (l *layers) flush() []overlay {
+	overlays := l.overlays
+	l.overlays = nil
+	sort.SliceStable(overlays, func(i, j int) bool {
+		return overlays[i].z < overlays[j].z
+	})
+	return overlays
+}
@@ -0,0 +1,64 @@
+package receipt
+
+import (
+	xdraw "golang.org/x/image/draw"
+	"image"
+	"image/draw"
+)
+
+type picture struct {
+	img   image.Image
+	width Measure
+}
+
+// Picture draws the image (the logo, the QR code) scaled to the width with its proportions kept,
+// zero width means the size of the image in pixels. The picture is never wider than the container.
+// The monochrome canvas (see SetMonochrome) dithers the picture as it is drawn, the text around it is not dithered.
+// The pictures have no text representation and take no room on TextCanvas
+//
+//	Lines(Picture(logo, Millimeters(40)), Text("Corner Shop", OptionCentered()))
+func Picture(img image.Image, width Measure) DrawStruct {
+	return picture{
+		img:   img,
+		width: width,
+	}
+}
+
+// size returns the size of the picture in pixels which fits into the width
+func (p picture) size(width, maxWidth int) image.Point {
+	bounds := p.img.Bounds()
+	if bounds.Empty() {
+		return image.Point{}
+	}
+	if width <= 0 {
+		width = bounds.Dx()
+	}
+	if maxWidth > 0 && width > maxWidth {
+		width = maxWidth
+	}
+	return image.Point{X: width, Y: (bounds.Dy()*width + bounds.Dx()/2) / bounds.Dx()}
+}
+
+func (p picture) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	size := p.size(canvas.toPixel(p.width), rect.Dx())
+	if size.X <= 0 || size.Y <= 0 {
+		return rect.Min
+	}
+	if _, ok := canvas.img.(nullImage); !ok {
+		scaled := image.NewRGBA(image.Rectangle{Max: size})
+		xdraw.CatmullRom.Scale(scaled, scaled.Rect, p.img, p.img.Bounds(), draw.Src, nil)
+		if canvas.mono != nil {
+			canvas.mono.dither(scaled)
+		}
+		draw.Draw(canvas.img, image.Rectangle{Min: rect.Min, Max: rect.Min.Add(size)}, scaled, image.Point{}, draw.Over)
+	}
+	return image.Point{X: rect.Max.X, Y: rect.Min.Y + size.Y}
+}
+
+func (p picture) measureContent(m measurer) (minWidth, maxWidth int) {
+	width := m.toWidth(p.width)
+	if width <= 0 {
+		width = m.toWidth(pixels(p.size(0, 0).X))
+	}
+	return width, width
+}
@@ -13,22 +13,24 @@ const (
 	defaultFontSize = 12
 )
 
+// dpi is the resolution of the canvases which are created without their own one
 var dpi = 340.0
 
 type (
 	// Measure is a universal unit of measurement on canvas. Use this:
 	//  Millimeters, Inches, Pixels
 	Measure interface {
-		toPixel() int
-		toInch() float64
-		toMillimeter() float64
+		toPixel(dpi float64) int
+		toInch(dpi float64) float64
+		toMillimeter(dpi float64) float64
 	}
 	pixels      int
 	inch        float64
 	millimeters float64
 )
 
-// SetDPI allows you to set the resolution (dots per inch)
+// SetDPI allows you to set the default resolution (dots per inch) of the canvases created afterwards,
+// it is not safe to call it while rendering. Canvas.SetDPI sets the resolution of the single canvas
 func SetDPI(newDpi float64) {
 	dpi = newDpi
 }
@@ -38,12 +40,12 @@ func ZeroPixel() Measure {
 	return Pixels(0)
 }
 
-// NewRectangle allows you to create a rectangle using length measures:
+// NewRectangle allows you to create a rectangle using length measures at the default resolution:
 //	Millimeters, Inches, Pixels
 // example
 //	NewRectangle(ZeroPixel(), ZeroPixel(), Inches(1.0), Inches(1.0)) // box with sides 1 inch
 func NewRectangle(x0, y0, x1, y1 Measure) image.Rectangle {
-	return image.Rect(x0.toPixel(), y0.toPixel(), x1.toPixel(), y1.toPixel())
+	return image.Rect(x0.toPixel(dpi), y0.toPixel(dpi), x1.toPixel(dpi), y1.toPixel(dpi))
 }
 
 // Pixels - measures the distance in pixels
@@ -51,15 +53,15 @@ func Pixels(pix int) Measure {
 	return pixels(pix)
 }
 
-func (p pixels) toPixel() int {
+func (p pixels) toPixel(dpi float64) int {
 	return int(p)
 }
 
-func (p pixels) toInch() float64 {
+func (p pixels) toInch(dpi float64) float64 {
 	return float64(p) / dpi
 }
 
-func (p pixels) toMillimeter() float64 {
+func (p pixels) toMillimeter(dpi float64) float64 {
 	return (float64(p) / dpi) * mmInch
 }
 
@@ -68,15 +70,15 @@ func Inches(i float64) Measure {
 	return inch(i)
 }
 
-func (i inch) toPixel() int {
-	return int(math.Round(inchToPix(float64(i))))
+func (i inch) toPixel(dpi float64) int {
+	return int(math.Round(inchToPix(float64(i), dpi)))
 }
 
-func (i inch) toInch() float64 {
+func (i inch) toInch(dpi float64) float64 {
 	return float64(i)
 }
 
-func (i inch) toMillimeter() float64 {
+func (i inch) toMillimeter(dpi float64) float64 {
 	return float64(i) * mmInch
 }
 
@@ -85,15 +87,15 @@ func Millimeters(m float64) Measure {
 	return millimeters(m)
 }
 
-func (m millimeters) toPixel() int {
-	return int(math.Round(mmToPix(float64(m))))
+func (m millimeters) toPixel(dpi float64) int {
+	return int(math.Round(mmToPix(float64(m), dpi)))
 }
 
-func (m millimeters) toInch() float64 {
+func (m millimeters) toInch(dpi float64) float64 {
 	return mmToInch(float64(m))
 }
 
-func (m millimeters) toMillimeter() float64 {
+func (m millimeters) toMillimeter(dpi float64) float64 {
 	return float64(m)
 }
 
@@ -101,10 +103,10 @@ func mmToInch(mm float64) float64 {
 	return mm / mmInch
 }
 
-func inchToPix(i float64) float64 {
+func inchToPix(i, dpi float64) float64 {
 	return i * dpi
 }
 
-func mmToPix(mm float64) float64 {
-	return inchToPix(mmToInch(mm))
+func mmToPix(mm, dpi float64) float64 {
+	return inchToPix(mmToInch(mm), dpi)
 }
@@ -0,0 +1,184 @@
+package receipt
+
+import (
+	"image"
+	"image/draw"
+)
+
+// RollCanvas is the canvas of the continuous paper roll (58 or 80 mm receipts): the width is fixed
+// and the image grows in chunks of rows as the objects are written. The rows which are finished
+// can be streamed to the printer in bands, then they are not kept in memory
+type RollCanvas struct {
+	width int
+	chunk int
+	dpi   float64
+	faces *faceCache
+	mono  *monochromeSettings
+	// layers keeps the objects anchored to the bottom or to the center of the page until the roll is closed
+	layers *layers
+	// img keeps the rows which are not streamed yet, its bounds start at the first of them
+	img   *image.RGBA
+	point image.Point
+	band  int
+	sink  func(*image.RGBA) error
+	err   error
+}
+
+const defaultRollChunk = 512
+
+// NewRollCanvas creates the roll of the width, the image is white until the objects are written
+//
+//	roll := NewRollCanvas(Millimeters(72))
+//	roll.Write(receipt)
+//	if err := roll.Close(); err != nil {
+//		...
+//	}
+//	png.Encode(f, roll.Image())
+func NewRollCanvas(width Measure) *RollCanvas {
+	return NewRollCanvasDPI(width, dpi)
+}
+
+// NewRollCanvasDPI creates the roll of the width at the resolution (dots per inch),
+// e.g. 203 for most thermal receipt printers
+func NewRollCanvasDPI(width Measure, dpi float64) *RollCanvas {
+	return &RollCanvas{
+		width:  width.toPixel(dpi),
+		chunk:  defaultRollChunk,
+		dpi:    dpi,
+		faces:  newFaceCache(),
+		layers: &layers{deferAnchored: true},
+		img:    image.NewRGBA(image.Rect(0, 0, width.toPixel(dpi), 0)),
+	}
+}
+
+// SetChunk sets the number of rows the image grows by, 512 by default
+func (r *RollCanvas) SetChunk(rows int) {
+	if rows > 0 {
+		r.chunk = rows
+	}
+}
+
+// SetMonochrome prepares the roll for the output to the thermal printer, see Canvas.SetMonochrome
+func (r *RollCanvas) SetMonochrome(options ...MonochromeOption) {
+	s := newMonochromeSettings(options)
+	r.mono = &s
+}
+
+// StreamBands passes the finished rows to the function in bands of the height as soon as they are written,
+// the last band passed by Close can be lower. The band is reused after the function returns, so it must be
+// printed (or copied) by then. If the function fails, streaming stops and Close returns the error.
+// The objects which draw above the rows written so far (Absolute, overlays) can not change the streamed rows,
+// the rows the objects anchored to the bottom or to the center may take are kept until Close
+func (r *RollCanvas) StreamBands(height int, f func(band *image.RGBA) error) {
+	r.band = height
+	r.sink = f
+}
+
+// Write lays out the block of objects below the previous one, the image grows to fit it
+func (r *RollCanvas) Write(d DrawStruct) image.Point {
+	rect := image.Rect(0, r.point.Y, r.width, r.point.Y)
+	// the block is measured first to allocate the rows it takes
+	r.grow(d.WriteTo(r.canvas().measure(), rect).Y)
+	r.point = d.WriteTo(r.canvas(), rect)
+	r.grow(r.point.Y)
+	r.stream(false)
+	return r.point
+}
+
+// canvas draws onto the rows which are not streamed yet, the page is the part of the roll allocated so far.
+// The objects anchored to the bottom or to the center of the page are kept until the height is known
+func (r *RollCanvas) canvas() Canvas {
+	canvas := NewCanvas(r.img, image.Rect(0, 0, r.width, r.img.Rect.Max.Y))
+	canvas.SetDPI(r.dpi)
+	canvas.faces = r.faces
+	canvas.mono = r.mono
+	canvas.layers = r.layers
+	return canvas
+}
+
+// page is the canvas of the rows written so far, the anchored objects are placed on it
+func (r *RollCanvas) page() Canvas {
+	canvas := NewCanvas(r.img, image.Rect(0, 0, r.width, r.point.Y))
+	canvas.SetDPI(r.dpi)
+	canvas.faces = r.faces
+	canvas.mono = r.mono
+	return canvas
+}
+
+// anchoredTop returns the highest row the anchored objects may take. The objects move down as the roll grows,
+// so the rows above the place they would take on the current page are finished
+func (r *RollCanvas) anchoredTop() int {
+	var (
+		top  = r.point.Y
+		page = r.page()
+		t    = canvasTarget(page.measure(), pixels(0))
+	)
+	for _, a := range r.layers.anchored {
+		if y := a.place(t, page.rect).Min.Y; y < top {
+			top = y
+		}
+	}
+	return top
+}
+
+// Image returns the rows written and not streamed yet, it is the whole receipt if the bands are not streamed
+func (r *RollCanvas) Image() *image.RGBA {
+	bottom := r.point.Y
+	if bottom < r.img.Rect.Min.Y {
+		bottom = r.img.Rect.Min.Y
+	}
+	return r.img.SubImage(image.Rect(0, r.img.Rect.Min.Y, r.width, bottom)).(*image.RGBA)
+}
+
+// Close draws the objects anchored to the bottom or to the center of the roll, which is as high as the rows
+// written, then streams the rest of the rows and returns the error of streaming
+func (r *RollCanvas) Close() error {
+	page := r.page()
+	for _, a := range r.layers.anchored {
+		a.WriteTo(page, page.rect)
+	}
+	r.layers.anchored = nil
+	r.stream(true)
+	return r.err
+}
+
+// grow makes the image high enough for the rows up to the bottom, the new rows are white
+func (r *RollCanvas) grow(bottom int) {
+	if bottom <= r.img.Rect.Max.Y {
+		return
+	}
+	var (
+		rows = (bottom - r.img.Rect.Min.Y + r.chunk - 1) / r.chunk * r.chunk
+		img  = image.NewRGBA(image.Rect(0, r.img.Rect.Min.Y, r.width, r.img.Rect.Min.Y+rows))
+	)
+	copy(img.Pix, r.img.Pix)
+	draw.Draw(img, image.Rect(0, r.img.Rect.Max.Y, r.width, img.Rect.Max.Y), image.White, image.Point{}, draw.Src)
+	r.img = img
+}
+
+// stream passes the finished bands to the sink and drops them, all the written rows are finished when the roll is closed
+func (r *RollCanvas) stream(all bool) {
+	if r.sink == nil || r.err != nil || r.band <= 0 {
+		return
+	}
+	finished := r.point.Y
+	if len(r.layers.anchored) > 0 {
+		finished = r.anchoredTop()
+	}
+	for finished-r.img.Rect.Min.Y >= r.band || (all && r.point.Y > r.img.Rect.Min.Y) {
+		var (
+			top    = r.img.Rect.Min.Y
+			bottom = minInt(top+r.band, r.point.Y)
+		)
+		if r.err = r.sink(r.img.SubImage(image.Rect(0, top, r.width, bottom)).(*image.RGBA)); r.err != nil {
+			return
+		}
+		// the rows left are moved to the start of the buffer which keeps its size, the freed rows are white
+		var (
+			rows  = bottom - top
+			moved = copy(r.img.Pix, r.img.Pix[rows*r.img.Stride:])
+		)
+		r.img.Rect = r.img.Rect.Add(image.Point{Y: rows})
+		draw.Draw(r.img, image.Rect(0, r.img.Rect.Min.Y+moved/r.img.Stride, r.width, r.img.Rect.Max.Y), image.White, image.Point{}, draw.Src)
+	}
+}
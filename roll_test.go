@@ -0,0 +1,104 @@
+package receipt
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+// sameRows compares the pixels of the rows of the images
+func sameRows(a, b *image.RGBA, from, to int) bool {
+	for y := from; y < to; y++ {
+		if !bytes.Equal(a.Pix[a.PixOffset(a.Rect.Min.X, y):a.PixOffset(a.Rect.Max.X, y)], b.Pix[b.PixOffset(b.Rect.Min.X, y):b.PixOffset(b.Rect.Max.X, y)]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRollAnchoredToBottom(t *testing.T) {
+	var (
+		body = func() DrawStruct {
+			rows := make([]DrawStruct, 5)
+			for i := range rows {
+				rows[i] = Text("line")
+			}
+			return Lines(rows...)
+		}
+		stamp  = Absolute(AnchorBottomRight, Pixels(0), Pixels(0), Pixels(0), Text("STAMP"))
+		render = func(band int, d ...DrawStruct) *image.RGBA {
+			var (
+				roll    = NewRollCanvasDPI(Millimeters(72), 203)
+				streams []*image.RGBA
+			)
+			if band > 0 {
+				roll.StreamBands(band, func(img *image.RGBA) error {
+					copied := image.NewRGBA(img.Rect)
+					copy(copied.Pix, img.Pix)
+					streams = append(streams, copied)
+					return nil
+				})
+			}
+			for _, block := range d {
+				roll.Write(block)
+			}
+			if err := roll.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if band == 0 {
+				return roll.Image()
+			}
+			img := image.NewRGBA(image.Rect(0, 0, roll.width, roll.point.Y))
+			for _, s := range streams {
+				for y := s.Rect.Min.Y; y < s.Rect.Max.Y; y++ {
+					copy(img.Pix[img.PixOffset(0, y):img.PixOffset(roll.width, y)], s.Pix[s.PixOffset(0, y):s.PixOffset(roll.width, y)])
+				}
+			}
+			return img
+		}
+		plain   = render(0, body())
+		stamped = render(0, stamp, body())
+	)
+	if plain.Rect.Dy() != stamped.Rect.Dy() {
+		t.Fatalf("the stamp changes the height of the roll from %d to %d", plain.Rect.Dy(), stamped.Rect.Dy())
+	}
+	height := stamped.Rect.Dy()
+	if sameRows(plain, stamped, 0, height) {
+		t.Fatal("the stamp is not drawn")
+	}
+	if !sameRows(plain, stamped, 0, height/2) {
+		t.Error("the stamp is drawn in the upper half of the roll, the bottom is expected")
+	}
+	if streamed := render(16, stamp, body()); !sameRows(streamed, stamped, 0, height) {
+		t.Error("the streamed roll differs from the roll drawn at once")
+	}
+}
+
+func TestRollAnchoredToCenter(t *testing.T) {
+	var (
+		roll  = NewRollCanvasDPI(Millimeters(72), 203)
+		rows  = make([]DrawStruct, 20)
+		bands int
+	)
+	for i := range rows {
+		rows[i] = Text("line")
+	}
+	roll.StreamBands(8, func(*image.RGBA) error {
+		bands++
+		return nil
+	})
+	roll.Write(Absolute(AnchorCenter, Pixels(0), Pixels(0), Pixels(0), Text("VOID")))
+	for _, row := range rows {
+		roll.Write(row)
+	}
+	// the rows above the middle of the roll are finished
+	if bands == 0 {
+		t.Error("no band is streamed before the roll is closed")
+	}
+	if written := bands * 8; written > roll.point.Y/2 {
+		t.Errorf("%d rows of %d are streamed, the middle of the roll is not finished", written, roll.point.Y)
+	}
+	if err := roll.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,160 @@
+package receipt
+
+import (
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/math/f64"
+	"image"
+	"image/draw"
+	"math"
+)
+
+type rotate struct {
+	angle float64
+	draw  DrawStruct
+}
+
+// Rotate draws the object turned counterclockwise by the angle in degrees, e.g. 90 for the text
+// which reads from the bottom up along the side of a label or 45 for a watermark.
+// The object is laid out in its own coordinate system at the width of its content
+// (limited by the room available for it) and takes the space of its rotated bounding box in the flow.
+// On TextCanvas the objects turned by 90 or 270 degrees are written letter under letter, other angles are ignored
+//
+//	Rotate(90, Text("FRAGILE"))
+func Rotate(angle float64, d DrawStruct) DrawStruct {
+	return rotate{
+		angle: math.Mod(math.Mod(angle, 360)+360, 360),
+		draw:  d,
+	}
+}
+
+// sinCos returns the sine and the cosine of the angle, right angles are exact
+func (r rotate) sinCos() (sin, cos float64) {
+	sin, cos = math.Sincos(r.angle * math.Pi / 180)
+	if math.Mod(r.angle, 90) == 0 {
+		sin, cos = math.Round(sin), math.Round(cos)
+	}
+	return sin, cos
+}
+
+// layoutSize finds the size of the object in its own coordinate system,
+// the room is the width and the height of the rectangle the rotated object is placed into
+func (r rotate) layoutSize(t layoutTarget, room image.Rectangle) image.Point {
+	_, width := measureStruct(t.m, r.draw)
+	sin, _ := r.sinCos()
+	limit := room.Dx()
+	if math.Abs(sin) == 1 {
+		// the width of the object turned on its side is limited by the height of the room, if it is known
+		limit = room.Dy()
+	}
+	if width <= 0 || (limit > 0 && width > limit) {
+		width = limit
+	}
+	return image.Point{X: width, Y: t.height(r.draw, image.Rect(0, 0, width, 0))}
+}
+
+// bounds returns the affine transformation of the object of the size and the size of its rotated bounding box
+func (r rotate) bounds(size image.Point, at image.Point) (f64.Aff3, image.Point) {
+	var (
+		sin, cos   = r.sinCos()
+		minX, minY = math.Inf(1), math.Inf(1)
+		maxX, maxY = math.Inf(-1), math.Inf(-1)
+	)
+	// the y axis of the image looks down, so the counterclockwise turn is (x cos + y sin, y cos - x sin)
+	for _, p := range []image.Point{{0, 0}, {size.X, 0}, {0, size.Y}, {size.X, size.Y}} {
+		x := float64(p.X)*cos + float64(p.Y)*sin
+		y := float64(p.Y)*cos - float64(p.X)*sin
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+	return f64.Aff3{
+		cos, sin, float64(at.X) - minX,
+		-sin, cos, float64(at.Y) - minY,
+	}, image.Point{
+		X: int(math.Ceil(maxX - minX)),
+		Y: int(math.Ceil(maxY - minY)),
+	}
+}
+
+func (r rotate) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	size := r.layoutSize(canvasTarget(canvas, pixels(0)), rect)
+	if size.X <= 0 || size.Y <= 0 {
+		return rect.Min
+	}
+	transform, box := r.bounds(size, rect.Min)
+	if _, ok := canvas.img.(nullImage); !ok {
+		// the object is drawn on the transparent layer which is turned and composited onto the canvas,
+		// the layer has the margin for the glyphs which stick out of the box
+		var (
+			area                            = image.Rect(0, 0, size.X, size.Y)
+			layer                           = image.NewRGBA(area.Inset(-size.Y / 2))
+			interpolator xdraw.Interpolator = xdraw.BiLinear
+		)
+		inner := canvas
+		inner.img, inner.rect = layer, area
+		r.draw.WriteTo(inner, area)
+		// the monochrome canvas gets no grey dots from the interpolation
+		if math.Mod(r.angle, 90) == 0 || canvas.mono != nil {
+			interpolator = xdraw.NearestNeighbor
+		}
+		interpolator.Transform(canvas.img, transform, layer, layer.Rect, draw.Over, nil)
+	}
+	return image.Point{X: rect.Min.X + box.X, Y: rect.Min.Y + box.Y}
+}
+
+func (r rotate) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	if r.angle != 90 && r.angle != 270 {
+		return c.writeStruct(r.draw, rect)
+	}
+	var (
+		size    = r.layoutSize(textTarget(c, pixels(0)), rect)
+		scratch = c.scratch()
+	)
+	scratch.writeStruct(r.draw, image.Rect(0, 0, size.X, size.Y))
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			ch := scratch.charAt(image.Point{X: x, Y: y})
+			if ch == ' ' {
+				continue
+			}
+			// the text read from the bottom up starts at the bottom line of the block
+			p := image.Point{X: rect.Min.X + y, Y: rect.Min.Y + size.X - 1 - x}
+			if r.angle == 270 {
+				p = image.Point{X: rect.Min.X + size.Y - 1 - y, Y: rect.Min.Y + x}
+			}
+			c.putString(p.X, p.Y, string(turnedRune(ch)))
+		}
+	}
+	return image.Point{X: rect.Min.X + size.Y, Y: rect.Min.Y + size.X}
+}
+
+// turnedRune swaps the horizontal and vertical lines of the borders
+func turnedRune(ch rune) rune {
+	switch ch {
+	case '-':
+		return '|'
+	case '|':
+		return '-'
+	case '─':
+		return '│'
+	case '│':
+		return '─'
+	}
+	return ch
+}
+
+func (r rotate) measureContent(m measurer) (minWidth, maxWidth int) {
+	var t layoutTarget
+	switch v := m.(type) {
+	case Canvas:
+		t = canvasTarget(v, pixels(0))
+	case *TextCanvas:
+		if r.angle != 90 && r.angle != 270 {
+			return measureStruct(m, r.draw)
+		}
+		t = textTarget(v, pixels(0))
+	default:
+		return measureStruct(m, r.draw)
+	}
+	_, box := r.bounds(r.layoutSize(t, image.Rectangle{}), image.Point{})
+	return box.X, box.X
+}
@@ -0,0 +1,170 @@
+package receipt
+
+import (
+	"golang.org/x/image/math/f64"
+	"image"
+	"image/draw"
+	"math"
+)
+
+type rule struct {
+	usePen   pen
+	length   Measure
+	style    LineStyle
+	vertical bool
+}
+
+// the characters of the rules on TextCanvas: ASCII and box-drawing ones for the horizontal and for the vertical rules
+var ruleChars = map[LineStyle][2][2]rune{
+	LineSolid:  {{'-', '|'}, {'─', '│'}},
+	LineDashed: {{'-', '|'}, {'╌', '╎'}},
+	LineDotted: {{'.', ':'}, {'┈', '┊'}},
+	LineDouble: {{'=', '|'}, {'═', '║'}},
+}
+
+// HRule draws the horizontal line (the separator of the receipt) with the pen, the line is as high as the pen is
+// thick (three times thicker for LineDouble). Zero length means the whole width of the container
+//
+//	Lines(items, HRule(NewPen(color.Black, Pixels(2)), Pixels(0), LineDashed), totals)
+func HRule(usePen pen, length Measure, style LineStyle) DrawStruct {
+	return rule{
+		usePen: usePen,
+		length: length,
+		style:  style,
+	}
+}
+
+// VRule draws the vertical line, e.g. between the columns of Cols. Zero length means the height of the container,
+// in Cols it is the height of the row which is known after the other columns are drawn
+func VRule(usePen pen, length Measure, style LineStyle) DrawStruct {
+	return rule{
+		usePen:   usePen,
+		length:   length,
+		style:    style,
+		vertical: true,
+	}
+}
+
+// thickness returns the exact size of the rule across the line at the resolution, the rule is anti-aliased
+// and is not snapped to whole pixels
+func (r rule) thickness(dpi float64) float64 {
+	if r.style == LineDouble {
+		return 3 * r.usePen.thickness(dpi)
+	}
+	return r.usePen.thickness(dpi)
+}
+
+// fillsRow reports whether the object is the vertical rule of zero length (in any units),
+// which takes the height of the row of Cols
+func fillsRow(d DrawStruct) bool {
+	switch v := d.(type) {
+	case colWidth:
+		return fillsRow(v.draw)
+	case rule:
+		return v.vertical && v.length.toInch(dpi) <= 0
+	default:
+		return false
+	}
+}
+
+func (r rule) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	var (
+		length    = canvas.toPixel(r.length)
+		thickness = int(math.Ceil(r.thickness(canvas.dpi)))
+		size      image.Point
+	)
+	if r.vertical {
+		if length <= 0 {
+			length = rect.Dy()
+		}
+		size = image.Point{X: thickness, Y: length}
+	} else {
+		if length <= 0 {
+			length = rect.Dx()
+		}
+		size = image.Point{X: length, Y: thickness}
+	}
+	if _, ok := canvas.img.(nullImage); !ok && length > 0 && thickness > 0 {
+		mask := canvas.fillMask(size, r.outlines(length, r.usePen.thickness(canvas.dpi))...)
+		draw.DrawMask(canvas.img, image.Rectangle{Min: rect.Min, Max: rect.Min.Add(size)}, image.NewUniform(r.usePen.color), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+	if r.vertical {
+		return image.Point{X: rect.Min.X + size.X, Y: rect.Min.Y + size.Y}
+	}
+	return image.Point{X: rect.Max.X, Y: rect.Min.Y + size.Y}
+}
+
+// outlines returns the polygons of the dashes of the rule of the width, the dots are round
+func (r rule) outlines(length int, width float64) [][]f64.Vec2 {
+	var (
+		dash, period = dashPattern(r.style, width)
+		outlines     [][]f64.Vec2
+		// the polygons are made for the horizontal rule and turned for the vertical one
+		add = func(start, end, top, radius float64) {
+			min, max := f64.Vec2{start, top}, f64.Vec2{end, top + width}
+			if r.vertical {
+				min, max = f64.Vec2{top, start}, f64.Vec2{top + width, end}
+			}
+			radius = math.Min(radius, (end-start)/2)
+			corner := f64.Vec2{radius, radius}
+			outlines = append(outlines, roundedRect(min, max, [4]f64.Vec2{corner, corner, corner, corner}))
+		}
+	)
+	switch r.style {
+	case LineDashed, LineDotted:
+		var radius float64
+		if r.style == LineDotted {
+			radius = width / 2
+		}
+		for start := 0.; start < float64(length); start += period {
+			add(start, math.Min(start+dash, float64(length)), 0, radius)
+		}
+	case LineDouble:
+		add(0, float64(length), 0, 0)
+		add(0, float64(length), 2*width, 0)
+	default:
+		add(0, float64(length), 0, 0)
+	}
+	return outlines
+}
+
+func (r rule) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	var (
+		chars  = ruleChars[r.style][c.borders]
+		length int
+	)
+	if r.vertical {
+		if length = c.toLines(r.length); length <= 0 {
+			length = rect.Dy()
+		}
+		for y := rect.Min.Y; y < rect.Min.Y+length; y++ {
+			if r.style != LineDashed || c.borders == BordersBox || (y-rect.Min.Y)%2 == 0 {
+				c.putString(rect.Min.X, y, string(chars[1]))
+			}
+		}
+		return image.Point{X: rect.Min.X + 1, Y: rect.Min.Y + length}
+	}
+	if length = c.toColumns(r.length); length <= 0 {
+		length = rect.Dx()
+	}
+	for x := rect.Min.X; x < rect.Min.X+length; x++ {
+		// the ASCII dashes are separated by spaces
+		if r.style != LineDashed || c.borders == BordersBox || (x-rect.Min.X)%2 == 0 {
+			c.putString(x, rect.Min.Y, string(chars[0]))
+		}
+	}
+	return image.Point{X: rect.Max.X, Y: rect.Min.Y + 1}
+}
+
+func (r rule) measureContent(m measurer) (minWidth, maxWidth int) {
+	if !r.vertical {
+		// the rule of the whole width has no width of its own
+		w := m.toWidth(r.length)
+		return w, w
+	}
+	if _, ok := m.(*TextCanvas); ok {
+		return 1, 1
+	}
+	w := int(math.Ceil(r.thickness(m.DPI())))
+	return w, w
+}
@@ -0,0 +1,68 @@
+package receipt
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"testing"
+)
+
+func TestRuleExactThickness(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 10))
+	draw.Draw(img, img.Rect, image.White, image.Point{}, draw.Src)
+	canvas := NewCanvas(img, img.Rect)
+	canvas.SetDPI(203)
+	// 0.3 mm is about 2.4 pixels at 203 dpi
+	end := canvas.Write(HRule(NewPen(color.Black, Millimeters(0.3)), Pixels(0), LineSolid))
+	if end.Y != 3 {
+		t.Errorf("the rule is %d pixels high, 3 expected", end.Y)
+	}
+	for y, want := range []struct{ min, max uint8 }{{0, 0}, {0, 0}, {100, 200}} {
+		if got := img.RGBAAt(20, y).R; got < want.min || got > want.max {
+			t.Errorf("the row %d is %d, %d..%d expected", y, got, want.min, want.max)
+		}
+	}
+	if got := img.RGBAAt(20, 3).R; got != 0xff {
+		t.Errorf("the row below the rule is %d, white expected", got)
+	}
+}
+
+func TestVRuleInCols(t *testing.T) {
+	row := func() DrawStruct {
+		return Lines(Cols(Text("left"), VRule(NewPen(color.Black, Pixels(2)), Pixels(0), LineSolid), Text("right")), Text("next"))
+	}
+	t.Run("pixels", func(t *testing.T) {
+		canvas := testCanvas(400, 200)
+		height := Cols(Text("left")).WriteTo(canvas, image.Rect(0, 0, 400, 0)).Y
+		canvas = testCanvas(400, 200)
+		canvas.Write(row())
+		var painted int
+		for x := 0; x < 400; x++ {
+			solid := true
+			for y := 0; y < height; y++ {
+				if _, _, _, a := canvas.img.At(x, y).RGBA(); a != 0xffff {
+					solid = false
+					break
+				}
+			}
+			if solid {
+				painted++
+			}
+		}
+		if painted != 2 {
+			t.Errorf("%d columns are painted through the row of %d pixels, 2 expected", painted, height)
+		}
+	})
+	t.Run("text", func(t *testing.T) {
+		canvas := NewTextCanvas(32, Millimeters(72))
+		canvas.Write(row())
+		lines := strings.Split(canvas.String(), "\n")
+		if !strings.Contains(lines[0], "|") {
+			t.Errorf("the rule is not drawn in %q", lines[0])
+		}
+		if strings.Contains(lines[1], "|") {
+			t.Errorf("the rule is longer than the row: %q", lines[1])
+		}
+	})
+}
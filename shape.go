@@ -0,0 +1,436 @@
+package receipt
+
+import (
+	"golang.org/x/image/math/f64"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+type (
+	// DrawShape is the vector shape which can be customized with shape options
+	DrawShape interface {
+		WriteTo(Canvas, image.Rectangle) image.Point
+		WithOptions(options ...ShapeOption) DrawShape
+	}
+	// ShapeOption changes the look of the shape:
+	//  OptionStroke, OptionFill
+	ShapeOption interface {
+		shapeOptInt() int
+	}
+	// Vertex is the point of the shape, the coordinates are measured from the top left corner of the shape
+	Vertex struct {
+		X Measure
+		Y Measure
+	}
+	// Path is the outline of the shape made of the straight and curved segments, e.g.
+	//
+	//	NewPath().MoveTo(Pixels(0), Pixels(10)).LineTo(Pixels(8), Pixels(18)).LineTo(Pixels(24), Pixels(0))
+	Path struct {
+		segments []pathSegment
+	}
+	pathOp      int
+	pathSegment struct {
+		op     pathOp
+		points []Vertex
+	}
+
+	shape struct {
+		path   Path
+		width  Measure
+		height Measure
+		stroke pen
+		fill   color.Color
+	}
+	shapeStroke struct {
+		usePen pen
+	}
+	shapeFill struct {
+		color color.Color
+	}
+	// scaledMeasure is the part of the measure, the ellipses are made of them to keep the units of their size
+	scaledMeasure struct {
+		m Measure
+		k float64
+	}
+)
+
+const (
+	pathMoveTo pathOp = iota
+	pathLineTo
+	pathQuadTo
+	pathCubicTo
+	pathClose
+)
+
+// NewPath starts the empty path
+func NewPath() Path {
+	return Path{}
+}
+
+// MoveTo starts the new part of the path at the point
+func (p Path) MoveTo(x, y Measure) Path {
+	return p.add(pathMoveTo, Vertex{X: x, Y: y})
+}
+
+// LineTo adds the straight line from the current point to the point
+func (p Path) LineTo(x, y Measure) Path {
+	return p.add(pathLineTo, Vertex{X: x, Y: y})
+}
+
+// QuadTo adds the quadratic Bézier curve with the control point (cx, cy) to the point
+func (p Path) QuadTo(cx, cy, x, y Measure) Path {
+	return p.add(pathQuadTo, Vertex{X: cx, Y: cy}, Vertex{X: x, Y: y})
+}
+
+// CubicTo adds the cubic Bézier curve with two control points to the point
+func (p Path) CubicTo(c1x, c1y, c2x, c2y, x, y Measure) Path {
+	return p.add(pathCubicTo, Vertex{X: c1x, Y: c1y}, Vertex{X: c2x, Y: c2y}, Vertex{X: x, Y: y})
+}
+
+// Close joins the current point with the start of the part of the path
+func (p Path) Close() Path {
+	return p.add(pathClose)
+}
+
+func (p Path) add(op pathOp, points ...Vertex) Path {
+	segments := make([]pathSegment, len(p.segments), len(p.segments)+1)
+	copy(segments, p.segments)
+	p.segments = append(segments, pathSegment{op: op, points: points})
+	return p
+}
+
+// Shape draws the path, the shape is as large as the path (and the stroke around it)
+func Shape(path Path) DrawShape {
+	return shape{
+		path:   path,
+		stroke: defaultPen,
+	}
+}
+
+// Segment draws the straight line between two points, e.g. the line for the signature
+func Segment(x0, y0, x1, y1 Measure) DrawShape {
+	return Shape(NewPath().MoveTo(x0, y0).LineTo(x1, y1))
+}
+
+// Polyline draws the lines through the points
+func Polyline(points ...Vertex) DrawShape {
+	return Shape(polyPath(points))
+}
+
+// Polygon draws the closed outline through the points, use OptionFill to fill it
+func Polygon(points ...Vertex) DrawShape {
+	if len(points) == 0 {
+		return Shape(NewPath())
+	}
+	return Shape(polyPath(points).Close())
+}
+
+// Ellipse draws the ellipse which fits the rectangle of the width and the height, e.g. the circle of the radio button
+func Ellipse(width, height Measure) DrawShape {
+	return shape{
+		path:   ellipsePath(width, height, 0, 360).Close(),
+		width:  width,
+		height: height,
+		stroke: defaultPen,
+	}
+}
+
+// Arc draws the part of the ellipse which fits the rectangle of the width and the height.
+// The angles are in degrees counterclockwise from the three o'clock direction
+func Arc(width, height Measure, start, sweep float64) DrawShape {
+	return shape{
+		path:   ellipsePath(width, height, start, sweep),
+		width:  width,
+		height: height,
+		stroke: defaultPen,
+	}
+}
+
+// OptionStroke sets the pen the outline of the shape is drawn with, the pen with zero weight hides the outline
+func OptionStroke(usePen pen) ShapeOption {
+	return shapeStroke{usePen: usePen}
+}
+
+// OptionFill fills the closed parts of the shape with the color
+func OptionFill(c color.Color) ShapeOption {
+	return shapeFill{color: c}
+}
+
+func (_ shapeStroke) shapeOptInt() int {
+	return 0
+}
+
+func (_ shapeFill) shapeOptInt() int {
+	return 0
+}
+
+func polyPath(points []Vertex) Path {
+	path := NewPath()
+	for i, p := range points {
+		if i == 0 {
+			path = path.MoveTo(p.X, p.Y)
+			continue
+		}
+		path = path.LineTo(p.X, p.Y)
+	}
+	return path
+}
+
+// ellipsePath makes the arc of cubic curves, every curve is no longer than a quarter of the ellipse
+func ellipsePath(width, height Measure, start, sweep float64) Path {
+	var (
+		// the points are calculated in the parts of the width and the height
+		rx, ry = 0.5, 0.5
+		pieces = int(math.Ceil(math.Abs(sweep) / 90))
+		point  = func(angle float64) (float64, float64) {
+			// the y axis looks down, so the counterclockwise angle goes up
+			return rx + rx*math.Cos(angle), ry - ry*math.Sin(angle)
+		}
+		x    = func(k float64) Measure { return scaledMeasure{m: width, k: k} }
+		y    = func(k float64) Measure { return scaledMeasure{m: height, k: k} }
+		path = NewPath()
+	)
+	if pieces == 0 {
+		return path
+	}
+	var (
+		step = sweep / float64(pieces) * math.Pi / 180
+		a0   = start * math.Pi / 180
+		// the length of the tangents of the cubic curve which approximates the arc
+		k = 4. / 3 * math.Tan(step/4)
+	)
+	x0, y0 := point(a0)
+	path = path.MoveTo(x(x0), y(y0))
+	for i := 0; i < pieces; i++ {
+		var (
+			a1     = a0 + step
+			x1, y1 = point(a1)
+		)
+		path = path.CubicTo(
+			x(x0-k*rx*math.Sin(a0)), y(y0-k*ry*math.Cos(a0)),
+			x(x1+k*rx*math.Sin(a1)), y(y1+k*ry*math.Cos(a1)),
+			x(x1), y(y1),
+		)
+		a0, x0, y0 = a1, x1, y1
+	}
+	return path
+}
+
+func (s shape) WithOptions(options ...ShapeOption) DrawShape {
+	for _, opt := range options {
+		switch v := opt.(type) {
+		case shapeStroke:
+			s.stroke = v.usePen
+		case shapeFill:
+			s.fill = v.color
+		}
+	}
+	return s
+}
+
+// size returns the size of the shape with the stroke which sticks out of the path by a half of the pen
+func (s shape) size(parts []pathPart, dpi float64) image.Point {
+	var maxX, maxY float64
+	if s.width != nil && s.height != nil {
+		maxX, maxY = toPixelF(s.width, dpi), toPixelF(s.height, dpi)
+	} else {
+		for _, part := range parts {
+			for _, p := range part.points {
+				maxX, maxY = math.Max(maxX, p[0]), math.Max(maxY, p[1])
+			}
+		}
+	}
+	return image.Point{
+		X: int(math.Ceil(maxX)) + s.stroke.weight(dpi),
+		Y: int(math.Ceil(maxY)) + s.stroke.weight(dpi),
+	}
+}
+
+func (s shape) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	var (
+		parts  = s.path.flatten(canvas.dpi)
+		size   = s.size(parts, canvas.dpi)
+		weight = s.stroke.weight(canvas.dpi)
+	)
+	if _, ok := canvas.img.(nullImage); !ok {
+		var (
+			half    = float64(weight) / 2
+			offset  = f64.Vec2{half, half}
+			outline = image.Rectangle{Min: rect.Min, Max: rect.Min.Add(size)}
+		)
+		if s.fill != nil {
+			polygons := make([][]f64.Vec2, 0, len(parts))
+			for _, part := range parts {
+				polygons = append(polygons, translate(part.points, offset))
+			}
+			draw.DrawMask(canvas.img, outline, image.NewUniform(s.fill), image.Point{}, canvas.fillMask(size, polygons...), image.Point{}, draw.Over)
+		}
+		if weight > 0 {
+			var polygons [][]f64.Vec2
+			for _, part := range parts {
+				polygons = append(polygons, strokePolygons(translate(part.points, offset), part.closed, float64(weight))...)
+			}
+			draw.DrawMask(canvas.img, outline, image.NewUniform(s.stroke.color), image.Point{}, canvas.fillMask(size, polygons...), image.Point{}, draw.Over)
+		}
+	}
+	return image.Point{X: rect.Max.X, Y: rect.Min.Y + size.Y}
+}
+
+func (s shape) measureContent(m measurer) (minWidth, maxWidth int) {
+	w := m.toWidth(pixels(s.size(s.path.flatten(m.DPI()), m.DPI()).X))
+	return w, w
+}
+
+// pathPart is the flattened part of the path in pixels
+type pathPart struct {
+	points []f64.Vec2
+	closed bool
+}
+
+// flatten turns the curves of the path into the straight segments at the resolution
+func (p Path) flatten(dpi float64) []pathPart {
+	var (
+		parts   []pathPart
+		current pathPart
+		finish  = func() {
+			if len(current.points) > 1 {
+				parts = append(parts, current)
+			}
+			current = pathPart{}
+		}
+		last = func() f64.Vec2 {
+			if len(current.points) == 0 {
+				return f64.Vec2{}
+			}
+			return current.points[len(current.points)-1]
+		}
+	)
+	for _, segment := range p.segments {
+		points := make([]f64.Vec2, len(segment.points))
+		for i, v := range segment.points {
+			points[i] = f64.Vec2{toPixelF(v.X, dpi), toPixelF(v.Y, dpi)}
+		}
+		switch segment.op {
+		case pathMoveTo:
+			finish()
+			current.points = points
+		case pathLineTo:
+			current.points = append(current.points, points...)
+		case pathQuadTo, pathCubicTo:
+			current.points = append(current.points, flattenCurve(append([]f64.Vec2{last()}, points...))...)
+		case pathClose:
+			current.closed = true
+			start := current.points
+			finish()
+			if len(start) > 0 {
+				// the path goes on from the start of the closed part
+				current.points = []f64.Vec2{start[0]}
+			}
+		}
+	}
+	finish()
+	return parts
+}
+
+// flattenCurve returns the points of the Bézier curve with the control points (the first one is the start),
+// the start is not included. The number of the points depends on the length of the control polygon
+func flattenCurve(control []f64.Vec2) []f64.Vec2 {
+	var length float64
+	for i := 1; i < len(control); i++ {
+		length += math.Hypot(control[i][0]-control[i-1][0], control[i][1]-control[i-1][1])
+	}
+	var (
+		steps  = maxInt(int(math.Ceil(length/2)), 1)
+		points = make([]f64.Vec2, 0, steps)
+	)
+	for s := 1; s <= steps; s++ {
+		// de Casteljau's algorithm
+		var (
+			t = float64(s) / float64(steps)
+			q = append([]f64.Vec2{}, control...)
+		)
+		for n := len(q) - 1; n > 0; n-- {
+			for i := 0; i < n; i++ {
+				q[i] = f64.Vec2{q[i][0] + (q[i+1][0]-q[i][0])*t, q[i][1] + (q[i+1][1]-q[i][1])*t}
+			}
+		}
+		points = append(points, q[0])
+	}
+	return points
+}
+
+// strokePolygons returns the polygons which cover the line of the width along the points,
+// the joins and the ends of the line are round. All the polygons go clockwise, so they do not cut each other
+func strokePolygons(points []f64.Vec2, closed bool, width float64) [][]f64.Vec2 {
+	var (
+		half     = width / 2
+		corner   = f64.Vec2{half, half}
+		polygons = make([][]f64.Vec2, 0, 2*len(points))
+		segment  = func(a, b f64.Vec2) {
+			dx, dy := b[0]-a[0], b[1]-a[1]
+			length := math.Hypot(dx, dy)
+			if length == 0 {
+				return
+			}
+			nx, ny := -dy/length*half, dx/length*half
+			polygons = append(polygons, clockwise([]f64.Vec2{
+				{a[0] + nx, a[1] + ny}, {b[0] + nx, b[1] + ny},
+				{b[0] - nx, b[1] - ny}, {a[0] - nx, a[1] - ny},
+			}))
+		}
+	)
+	for i, p := range points {
+		polygons = append(polygons, roundedRect(
+			f64.Vec2{p[0] - half, p[1] - half},
+			f64.Vec2{p[0] + half, p[1] + half},
+			[4]f64.Vec2{corner, corner, corner, corner},
+		))
+		if i > 0 {
+			segment(points[i-1], p)
+		}
+	}
+	if closed && len(points) > 2 {
+		segment(points[len(points)-1], points[0])
+	}
+	return polygons
+}
+
+// clockwise turns the polygon clockwise (as it is seen with the y axis looking down)
+func clockwise(polygon []f64.Vec2) []f64.Vec2 {
+	var area float64
+	for i, p := range polygon {
+		q := polygon[(i+1)%len(polygon)]
+		area += p[0]*q[1] - q[0]*p[1]
+	}
+	if area < 0 {
+		return reversePath(polygon)
+	}
+	return polygon
+}
+
+func translate(points []f64.Vec2, offset f64.Vec2) []f64.Vec2 {
+	result := make([]f64.Vec2, len(points))
+	for i, p := range points {
+		result[i] = f64.Vec2{p[0] + offset[0], p[1] + offset[1]}
+	}
+	return result
+}
+
+// toPixelF converts the measure to pixels at the resolution without rounding
+func toPixelF(m Measure, dpi float64) float64 {
+	return inchToPix(m.toInch(dpi), dpi)
+}
+
+func (s scaledMeasure) toPixel(dpi float64) int {
+	return int(math.Round(toPixelF(s, dpi)))
+}
+
+func (s scaledMeasure) toInch(dpi float64) float64 {
+	return s.m.toInch(dpi) * s.k
+}
+
+func (s scaledMeasure) toMillimeter(dpi float64) float64 {
+	return s.m.toMillimeter(dpi) * s.k
+}
@@ -0,0 +1,284 @@
+package receipt
+
+import (
+	"image"
+	"math"
+	"strings"
+)
+
+type (
+	// Size describes how the width of a table column or of a child of Cols is calculated:
+	//  SizeAuto, SizeFixed, SizeFraction
+	// a column without a Size takes its pie fraction of the width
+	Size interface {
+		tableColumnOptInt() int
+		sizeOptInt() int
+	}
+	sizeAuto  struct{}
+	sizeFixed struct {
+		width Measure
+	}
+	sizeFraction struct {
+		pie float64
+	}
+	colWidth struct {
+		draw DrawStruct
+		size Size
+	}
+
+	// measurer converts widths of the content to the units of the target (pixels or characters)
+	measurer interface {
+		measureText(text string, style textStyle) (minWidth, maxWidth int)
+		toWidth(Measure) int
+		// DPI is the resolution the measures are converted at
+		DPI() float64
+		// borderWidth is the width the table border takes in addition to the content of the column
+		borderWidth() int
+	}
+	// contentMeasurer is implemented by objects able to report their min-content width
+	// (the widest unbreakable part) and max-content width (the width without any wrapping)
+	contentMeasurer interface {
+		measureContent(measurer) (minWidth, maxWidth int)
+	}
+	sizeTrack struct {
+		size       Size
+		minContent int
+		maxContent int
+	}
+)
+
+// SizeAuto means that the width of the column is calculated from the content of its header and cells,
+// the same way HTML tables do it: the content is not wrapped when there is enough room for it,
+// otherwise the width is distributed between min-content and max-content widths
+func SizeAuto() Size {
+	return sizeAuto{}
+}
+
+// SizeFixed sets the absolute width of the column, which does not depend on the width of the paper.
+// The rest of the width is shared by the other columns. If fixed columns do not fit into the table,
+// they are shrunk proportionally
+func SizeFixed(width Measure) Size {
+	return sizeFixed{width: width}
+}
+
+// SizeFraction sets the width as the share of the width which is left after fixed and auto sizes are taken.
+// If the fractions make up no more than a whole and there are no auto sizes, the share is taken of the whole width
+func SizeFraction(pie float64) Size {
+	return sizeFraction{pie: pie}
+}
+
+// ColWidth sets the width of the child of Cols. The children without ColWidth take the width of their content
+// (Fixed takes its own width), the rest of the row is shared by the fractions or by the auto-sized children
+//
+//	Cols(ColWidth(Text("Order #2231"), SizeFraction(1)), ColWidth(Text("DRAFT"), SizeFixed(Millimeters(20))))
+func ColWidth(d DrawStruct, size Size) DrawStruct {
+	return colWidth{
+		draw: d,
+		size: size,
+	}
+}
+
+func (c colWidth) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	return c.draw.WriteTo(canvas, rect)
+}
+
+func (c colWidth) writeGrid(g *TextCanvas, rect image.Rectangle) image.Point {
+	return g.writeStruct(c.draw, rect)
+}
+
+func (c colWidth) measureContent(m measurer) (minWidth, maxWidth int) {
+	if f, ok := c.size.(sizeFixed); ok {
+		w := m.toWidth(f.width)
+		return w, w
+	}
+	return measureStruct(m, c.draw)
+}
+
+func (c colWidth) defaultOptions(options ...TextOption) DrawStruct {
+	c.draw = withDefaultOptions(c.draw, options)
+	return c
+}
+
+// childSize returns the size of the child of Cols
+func childSize(d DrawStruct) Size {
+	switch v := d.(type) {
+	case colWidth:
+		return v.size
+	case fixedFiller:
+		return sizeFixed{width: v.x}
+	case contentMeasurer:
+		return sizeAuto{}
+	default:
+		// the content which can not be measured takes the rest of the row
+		return sizeFraction{pie: 1}
+	}
+}
+
+func (_ sizeAuto) tableColumnOptInt() int {
+	return 0
+}
+
+func (_ sizeAuto) sizeOptInt() int {
+	return 0
+}
+
+func (_ sizeFixed) tableColumnOptInt() int {
+	return 0
+}
+
+func (_ sizeFixed) sizeOptInt() int {
+	return 0
+}
+
+func (_ sizeFraction) tableColumnOptInt() int {
+	return 0
+}
+
+func (_ sizeFraction) sizeOptInt() int {
+	return 0
+}
+
+func measureStruct(m measurer, d DrawStruct) (minWidth, maxWidth int) {
+	if c, ok := d.(contentMeasurer); ok {
+		return c.measureContent(m)
+	}
+	return 0, 0
+}
+
+func (c Canvas) measureText(text string, style textStyle) (minWidth, maxWidth int) {
+	if style.font == nil {
+		style.font = getDefaultFont()
+	}
+	drawer := c.fontDrawer(nil, style)
+	return measureWords(text, func(s string) int {
+		return drawer.MeasureString(s).Ceil()
+	})
+}
+
+func (c Canvas) toWidth(m Measure) int {
+	return c.toPixel(m)
+}
+
+func (c Canvas) borderWidth() int {
+	// the borders are drawn over the content
+	return 0
+}
+
+func (c *TextCanvas) measureText(text string, _ textStyle) (minWidth, maxWidth int) {
+	return measureWords(text, func(s string) int {
+		return len([]rune(s))
+	})
+}
+
+func (c *TextCanvas) toWidth(m Measure) int {
+	return c.toColumns(m)
+}
+
+func (c *TextCanvas) borderWidth() int {
+	return 1
+}
+
+func measureWords(text string, width func(string) int) (minWidth, maxWidth int) {
+	for _, word := range strings.Split(text, " ") {
+		if w := width(word); w > minWidth {
+			minWidth = w
+		}
+	}
+	return minWidth, width(text)
+}
+
+// distributeSizes calculates the widths of the tracks so that they fill the total width.
+// Fixed tracks take their width first (the width of fixed tracks is kept in minContent),
+// then auto tracks take their content width, fractions share what is left in proportion to their pies.
+// If the pies of all the tracks make up no more than a whole, every fraction is taken from the total width.
+// The tracks which do not fit shrink below their content, the widths are never negative
+func distributeSizes(total int, tracks []sizeTrack) []int {
+	var (
+		widths    = make([]int, len(tracks))
+		remaining = total
+		sumPie    float64
+		autoMin   int
+		autoMax   int
+		fracMin   int
+		fixedSum  int
+		autoCount int
+	)
+	for _, t := range tracks {
+		switch s := t.size.(type) {
+		case sizeFixed:
+			fixedSum += t.minContent
+		case sizeAuto:
+			autoMin += t.minContent
+			autoMax += t.maxContent
+			autoCount++
+		case sizeFraction:
+			sumPie += s.pie
+			fracMin += t.minContent
+		}
+	}
+	if fixedSum > 0 {
+		var used int
+		for i, t := range tracks {
+			if _, ok := t.size.(sizeFixed); !ok {
+				continue
+			}
+			widths[i] = t.minContent
+			if fixedSum > total {
+				// the sum of the fixed widths never exceeds the total width
+				widths[i] = t.minContent * total / fixedSum
+			}
+			used += widths[i]
+		}
+		remaining -= used
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	if autoCount > 0 {
+		var (
+			budget = remaining - fracMin
+			used   int
+		)
+		for i, t := range tracks {
+			if _, ok := t.size.(sizeAuto); !ok {
+				continue
+			}
+			switch {
+			case autoMax <= budget && sumPie == 0 && autoMax > 0:
+				// there are no fractions, so auto columns grow to fill the whole width
+				widths[i] = t.maxContent + (budget-autoMax)*t.maxContent/autoMax
+			case autoMax <= budget && sumPie == 0:
+				widths[i] = budget / autoCount
+			case autoMax <= budget:
+				widths[i] = t.maxContent
+			case autoMin < budget:
+				widths[i] = t.minContent + (budget-autoMin)*(t.maxContent-t.minContent)/(autoMax-autoMin)
+			case autoMin <= remaining:
+				widths[i] = t.minContent
+			default:
+				// even the minimum widths do not fit, the auto tracks shrink in proportion to them
+				widths[i] = t.minContent * remaining / autoMin
+			}
+			used += widths[i]
+		}
+		remaining -= used
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	divider := sumPie
+	if autoCount == 0 && divider < 1 {
+		// the fractions are taken from the whole width, the rest of it stays empty
+		divider = 1
+	}
+	var pie, taken float64
+	for i, t := range tracks {
+		if s, ok := t.size.(sizeFraction); ok && divider > 0 {
+			// the boundaries are rounded rather than the widths, so the rounding errors do not accumulate
+			pie += s.pie
+			widths[i] = int(math.Round(float64(remaining)*pie/divider) - taken)
+			taken += float64(widths[i])
+		}
+	}
+	return widths
+}
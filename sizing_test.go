@@ -0,0 +1,53 @@
+package receipt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDistributeSizesOverflow(t *testing.T) {
+	var (
+		fixed = func(w int) sizeTrack { return sizeTrack{size: sizeFixed{}, minContent: w, maxContent: w} }
+		auto  = func(min, max int) sizeTrack { return sizeTrack{size: sizeAuto{}, minContent: min, maxContent: max} }
+		frac  = func(pie float64) sizeTrack { return sizeTrack{size: sizeFraction{pie: pie}} }
+	)
+	tests := []struct {
+		name   string
+		total  int
+		tracks []sizeTrack
+		want   []int
+	}{
+		{name: "auto wider than the total", total: 100, tracks: []sizeTrack{auto(150, 300), frac(1)}, want: []int{100, 0}},
+		{name: "fixed and auto", total: 100, tracks: []sizeTrack{fixed(60), auto(70, 90), frac(1)}, want: []int{60, 40, 0}},
+		{name: "auto shrink in proportion", total: 100, tracks: []sizeTrack{auto(150, 150), auto(50, 80)}, want: []int{75, 25}},
+		{name: "fixed take everything", total: 100, tracks: []sizeTrack{fixed(150), auto(20, 30), frac(1)}, want: []int{100, 0, 0}},
+		{name: "auto fit at the minimum", total: 100, tracks: []sizeTrack{fixed(30), auto(70, 90), frac(1)}, want: []int{30, 70, 0}},
+		{name: "fits", total: 100, tracks: []sizeTrack{fixed(30), auto(20, 30), frac(1)}, want: []int{30, 30, 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := distributeSizes(tt.total, tt.tracks)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("the widths are %v, %v expected", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnWidthsNarrowTable(t *testing.T) {
+	var (
+		canvas  = testCanvas(200, 100)
+		columns = []TableColumn{Column("Item", 0, SizeAuto()), Column("Sum", 1)}
+		tbl     = Table(columns, Cols(Text("averyveryverylongwordwhichdoesnotfit"), Text("1")))
+		total   int
+	)
+	for _, w := range tbl.(table).columnWidths(canvas, 200, canvas.borderWidth()) {
+		if w < 0 {
+			t.Errorf("the column is %d pixels wide", w)
+		}
+		total += w
+	}
+	if total > 200 {
+		t.Errorf("the columns are %d pixels wide, 200 expected at most", total)
+	}
+}
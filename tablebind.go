@@ -0,0 +1,256 @@
+package receipt
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	columnField struct {
+		name string
+	}
+	// boundRow is the table row generated from the struct or the map
+	boundRow struct {
+		record reflect.Value
+		cells  []DrawStruct
+	}
+	// boundCell keeps the original value of the field, so that aggregates do not parse the formatted text
+	// and are formatted the same way as the values
+	boundCell struct {
+		text
+		value     interface{}
+		formatter Formatter
+	}
+)
+
+const bindTag = "receipt"
+
+var (
+	formattersMx sync.RWMutex
+	formatters   = map[string]Formatter{
+		"money": moneyFormatter,
+		"int":   intFormatter,
+	}
+)
+
+// OptionField binds the column to the field of the struct (by the name of the field or by the name in the tag)
+// or to the key of the map, see TableOf
+//
+//	type OrderLine struct {
+//		Price float64 `receipt:"price,format=money"`
+//	}
+func OptionField(name string) ColumnOption {
+	return columnField{name: name}
+}
+
+func (_ columnField) tableColumnOptInt() int {
+	return 0
+}
+
+// RegisterFormatter makes the formatter available for the struct tags by the name: `receipt:"price,format=name"`.
+// The formatters "money" and "int" are registered by default
+func RegisterFormatter(name string, f Formatter) {
+	formattersMx.Lock()
+	formatters[name] = f
+	formattersMx.Unlock()
+}
+
+func lookupFormatter(name string) (Formatter, bool) {
+	formattersMx.RLock()
+	f, ok := formatters[name]
+	formattersMx.RUnlock()
+	return f, ok
+}
+
+// TableOf makes the table of the slice of structs (or pointers to structs) or maps with string keys.
+// The cells are filled with the values of the fields bound to the columns by OptionField
+func TableOf(columns []TableColumn, data interface{}) (DrawTable, error) {
+	rows, err := BindRows(columns, data)
+	if err != nil {
+		return nil, err
+	}
+	return Table(columns, rows...), nil
+}
+
+// BindRows generates the table rows from the slice of structs or maps, see TableOf.
+// The value is formatted by the formatter of the column, by the formatter from the struct tag or by default
+func BindRows(columns []TableColumn, data interface{}) ([]TableRow, error) {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot bind %T: slice expected", data)
+	}
+	rows := make([]TableRow, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		record := reflect.Indirect(v.Index(i))
+		for record.Kind() == reflect.Interface && !record.IsNil() {
+			record = reflect.Indirect(record.Elem())
+		}
+		row := boundRow{
+			record: record,
+			cells:  make([]DrawStruct, len(columns)),
+		}
+		for n, col := range columns {
+			field := col.getField()
+			if field == "" {
+				row.cells[n] = Empty()
+				continue
+			}
+			value, format, err := recordField(record, field)
+			if err != nil {
+				return nil, fmt.Errorf("cannot bind row %d: %w", i, err)
+			}
+			formatter := boundFormatter(col, format)
+			row.cells[n] = boundCell{
+				text:      text{text: formatter(value)},
+				value:     value,
+				formatter: formatter,
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func boundFormatter(col TableColumn, format string) Formatter {
+	if f := col.getFormatter(); f != nil {
+		return f
+	}
+	if format != "" {
+		if f, ok := lookupFormatter(format); ok {
+			return f
+		}
+	}
+	return defaultFormatter
+}
+
+// recordField finds the value of the field in the struct or in the map, the format is taken from the struct tag
+func recordField(record reflect.Value, name string) (value interface{}, format string, err error) {
+	switch record.Kind() {
+	case reflect.Map:
+		if record.Type().Key().Kind() != reflect.String {
+			return nil, "", fmt.Errorf("map key of %s is not a string", record.Type())
+		}
+		v := record.MapIndex(reflect.ValueOf(name).Convert(record.Type().Key()))
+		if !v.IsValid() {
+			return nil, "", nil
+		}
+		return v.Interface(), "", nil
+	case reflect.Struct:
+		v, tag, ok := structField(record, name)
+		if !ok {
+			return nil, "", fmt.Errorf("there is no field %q in %s", name, record.Type())
+		}
+		for _, opt := range strings.Split(tag, ",")[1:] {
+			if strings.HasPrefix(opt, "format=") {
+				format = strings.TrimPrefix(opt, "format=")
+			}
+		}
+		if !v.CanInterface() {
+			return nil, "", fmt.Errorf("field %q of %s is not exported", name, record.Type())
+		}
+		return v.Interface(), format, nil
+	case reflect.Invalid:
+		return nil, "", errors.New("nil record")
+	default:
+		return nil, "", fmt.Errorf("cannot bind %s: struct or map expected", record.Type())
+	}
+}
+
+// structField looks for the field by the name in the tag first and then by the name of the field,
+// the fields of embedded structs are searched as well
+func structField(record reflect.Value, name string) (reflect.Value, string, bool) {
+	var (
+		t        = record.Type()
+		embedded []int
+	)
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get(bindTag)
+		if tag == "-" {
+			continue
+		}
+		if strings.Split(tag, ",")[0] == name || t.Field(i).Name == name {
+			return record.Field(i), tag, true
+		}
+		if t.Field(i).Anonymous {
+			embedded = append(embedded, i)
+		}
+	}
+	for _, i := range embedded {
+		f := reflect.Indirect(record.Field(i))
+		if f.Kind() != reflect.Struct {
+			continue
+		}
+		if v, tag, ok := structField(f, name); ok {
+			return v, tag, true
+		}
+	}
+	return reflect.Value{}, "", false
+}
+
+func (r boundRow) getColumnByNum(num int) DrawStruct {
+	if num < len(r.cells) {
+		return r.cells[num]
+	}
+	return Empty()
+}
+
+func (c boundCell) numericValue(separator rune) (float64, bool) {
+	return toFloat(c.value, separator)
+}
+
+func (c boundCell) valueFormatter() Formatter {
+	return c.formatter
+}
+
+// toFloat converts the value to the number, the strings are parsed with the decimal separator (guessed if it is zero)
+func toFloat(value interface{}, separator rune) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		return parseNumber(v.String(), separator)
+	default:
+		return 0, false
+	}
+}
+
+// moneyFormatter prints the number with two decimals and groups the thousands with spaces: 1 200.50
+func moneyFormatter(v interface{}) string {
+	f, ok := toFloat(v, 0)
+	if !ok {
+		return defaultFormatter(v)
+	}
+	var (
+		s        = strconv.FormatFloat(f, 'f', 2, 64)
+		sign     = ""
+		intPart  = s[:len(s)-3]
+		fraction = s[len(s)-3:]
+		groups   []string
+	)
+	if strings.HasPrefix(intPart, "-") {
+		sign, intPart = "-", intPart[1:]
+	}
+	for len(intPart) > 3 {
+		groups = append([]string{intPart[len(intPart)-3:]}, groups...)
+		intPart = intPart[:len(intPart)-3]
+	}
+	groups = append([]string{intPart}, groups...)
+	return sign + strings.Join(groups, " ") + fraction
+}
+
+func intFormatter(v interface{}) string {
+	f, ok := toFloat(v, 0)
+	if !ok {
+		return defaultFormatter(v)
+	}
+	return strconv.FormatInt(int64(f), 10)
+}
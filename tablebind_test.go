@@ -0,0 +1,151 @@
+package receipt
+
+import (
+	"strings"
+	"testing"
+)
+
+type (
+	testProduct struct {
+		Name  string `receipt:"name"`
+		Price float64
+	}
+	testOrderLine struct {
+		testProduct
+		Supplier string  `receipt:"supplier"`
+		Count    int     `receipt:"count,format=int"`
+		Amount   float64 `receipt:"amount,format=money"`
+		Secret   string  `receipt:"-"`
+		internal string
+	}
+)
+
+func TestBindRowsFields(t *testing.T) {
+	var (
+		columns = []TableColumn{
+			Column("Name", 1, OptionField("name")),
+			Column("Price", 1, OptionField("Price")),
+			Column("Count", 1, OptionField("count")),
+			Column("Amount", 1, OptionField("amount")),
+			Column("Note", 1),
+		}
+		lines = []*testOrderLine{{
+			testProduct: testProduct{Name: "Beer", Price: 2.5},
+			Count:       12,
+			Amount:      1234.5,
+		}}
+	)
+	rows, err := BindRows(columns, lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Beer", "2.50", "12", "1 234.50", ""}
+	for i, w := range want {
+		var got string
+		if c, ok := rows[0].getColumnByNum(i).(boundCell); ok {
+			got = c.text.text
+		}
+		if got != w {
+			t.Errorf("column %d is %q, %q expected", i, got, w)
+		}
+	}
+}
+
+func TestBindRowsMaps(t *testing.T) {
+	var (
+		columns = []TableColumn{Column("Name", 1, OptionField("name")), Column("Count", 1, OptionField("count"))}
+		data    = []map[string]interface{}{{"name": "Beer", "count": 3}, {"name": "Wine"}}
+	)
+	rows, err := BindRows(columns, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rows[0].getColumnByNum(1).(boundCell).text.text; got != "3" {
+		t.Errorf("the count is %q, \"3\" expected", got)
+	}
+	if got := rows[1].getColumnByNum(1).(boundCell).text.text; got != "" {
+		t.Errorf("the missing key is %q, empty expected", got)
+	}
+}
+
+func TestBindRowsErrors(t *testing.T) {
+	columns := func(field string) []TableColumn {
+		return []TableColumn{Column("Field", 1, OptionField(field))}
+	}
+	tests := []struct {
+		name    string
+		columns []TableColumn
+		data    interface{}
+		err     string
+	}{
+		{name: "not a slice", columns: columns("name"), data: testOrderLine{}, err: "slice expected"},
+		{name: "no field", columns: columns("weight"), data: []testOrderLine{{}}, err: `no field "weight"`},
+		{name: "ignored field", columns: columns("Secret"), data: []testOrderLine{{}}, err: `no field "Secret"`},
+		{name: "not exported", columns: columns("internal"), data: []testOrderLine{{}}, err: "not exported"},
+		{name: "nil record", columns: columns("name"), data: []*testOrderLine{nil}, err: "nil record"},
+		{name: "map key", columns: columns("name"), data: []map[int]string{{1: "a"}}, err: "not a string"},
+		{name: "scalar", columns: columns("name"), data: []int{1}, err: "struct or map expected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := BindRows(tt.columns, tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("the error is %v, %q expected", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMoneyFormatter(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{in: 0, want: "0.00"},
+		{in: 999.999, want: "1 000.00"},
+		{in: 1234567.5, want: "1 234 567.50"},
+		{in: -5, want: "-5.00"},
+		{in: -1234.5, want: "-1 234.50"},
+		{in: -123456.78, want: "-123 456.78"},
+		{in: "1,200.5", want: "1 200.50"},
+		{in: "n/a", want: "n/a"},
+	}
+	for _, tt := range tests {
+		if got := moneyFormatter(tt.in); got != tt.want {
+			t.Errorf("moneyFormatter(%v) = %q, %q expected", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTableOfAggregates(t *testing.T) {
+	var (
+		columns = []TableColumn{
+			Column("Supplier", 1, OptionField("supplier")),
+			Column("Name", 2, OptionField("name")),
+			Column("Amount", 1, OptionField("amount")),
+		}
+		lines = []testOrderLine{
+			{testProduct: testProduct{Name: "Beer"}, Supplier: "Brewery", Amount: 1000},
+			{testProduct: testProduct{Name: "Ale"}, Supplier: "Brewery", Amount: 500.5},
+			{testProduct: testProduct{Name: "Wine"}, Supplier: "Winery", Amount: 20},
+		}
+	)
+	tbl, err := TableOf(columns, lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		d = tbl.WithOptions(
+			OptionGroupBy("supplier", Cols(ColSpan(Text("Subtotal"), 2), FooterSum())),
+			OptionFooter(Cols(Text("Total"), FooterCount(), FooterSum())),
+		)
+		canvas = NewTextCanvas(60, Millimeters(120))
+	)
+	canvas.Write(d)
+	out := canvas.String()
+	for _, want := range []string{"Subtotal", "1 500.50", "20.00", "1 520.50", "Total         |3 "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%q is missing in the table:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package receipt
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Formatter turns the value of the cell (or the result of the aggregate) into the text
+	Formatter func(v interface{}) string
+	// AggregateKind is the function calculated over the numeric values of the column:
+	//  AggregateSum, AggregateCount, AggregateAvg, AggregateMin, AggregateMax
+	AggregateKind int
+
+	aggregate struct {
+		kind    AggregateKind
+		options []TextOption
+	}
+	columnFormatter struct {
+		formatter Formatter
+	}
+	tableFooter struct {
+		rows []TableRow
+	}
+	tableFooterStyle struct {
+		options []CellOption
+	}
+	// numericCell is implemented by the cell contents which have a numeric value
+	numericCell interface {
+		numericValue(separator rune) (float64, bool)
+	}
+	// formattedCell is implemented by the cell contents which were formatted by the formatter, e.g. bound cells
+	formattedCell interface {
+		valueFormatter() Formatter
+	}
+	columnValues struct {
+		numbers   []float64
+		count     int
+		formatter Formatter
+	}
+)
+
+const (
+	AggregateSum AggregateKind = iota
+	AggregateCount
+	AggregateAvg
+	AggregateMin
+	AggregateMax
+)
+
+// FooterSum is the footer cell with the sum of the numeric values of the column
+func FooterSum(options ...TextOption) DrawStruct {
+	return aggregate{kind: AggregateSum, options: options}
+}
+
+// FooterCount is the footer cell with the number of non-empty cells of the column
+func FooterCount(options ...TextOption) DrawStruct {
+	return aggregate{kind: AggregateCount, options: options}
+}
+
+// FooterAvg is the footer cell with the average of the numeric values of the column
+func FooterAvg(options ...TextOption) DrawStruct {
+	return aggregate{kind: AggregateAvg, options: options}
+}
+
+// FooterMin is the footer cell with the minimum of the numeric values of the column
+func FooterMin(options ...TextOption) DrawStruct {
+	return aggregate{kind: AggregateMin, options: options}
+}
+
+// FooterMax is the footer cell with the maximum of the numeric values of the column
+func FooterMax(options ...TextOption) DrawStruct {
+	return aggregate{kind: AggregateMax, options: options}
+}
+
+// OptionFormatter sets the function which formats the values of the column, e.g. the results of the aggregates
+func OptionFormatter(f Formatter) ColumnOption {
+	return columnFormatter{formatter: f}
+}
+
+// OptionFooter adds the rows to the end of the table. Their cells can contain aggregates
+// (FooterSum, FooterCount, FooterAvg, FooterMin, FooterMax) which are calculated over the column they are placed in
+// (over the last column if the cell spans several), e.g.
+//
+//	OptionFooter(Cols(Text("Total"), FooterCount(), Empty(), FooterSum()))
+func OptionFooter(rows ...TableRow) TableOption {
+	return tableFooter{rows: rows}
+}
+
+// OptionFooterStyle sets the look of the footer cells
+func OptionFooterStyle(options ...CellOption) TableOption {
+	return tableFooterStyle{options: options}
+}
+
+func (_ columnFormatter) tableColumnOptInt() int {
+	return 0
+}
+
+func (_ tableFooter) tableOptInt() int {
+	return 0
+}
+
+func (_ tableFooterStyle) tableOptInt() int {
+	return 0
+}
+
+// WriteTo draws nothing, aggregates only make sense in the table footer
+func (a aggregate) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	return rect.Min
+}
+
+func (a aggregate) calculate(values columnValues) (interface{}, bool) {
+	if a.kind == AggregateCount {
+		return values.count, true
+	}
+	if len(values.numbers) == 0 {
+		return nil, false
+	}
+	var result = values.numbers[0]
+	for _, v := range values.numbers[1:] {
+		switch a.kind {
+		case AggregateSum, AggregateAvg:
+			result += v
+		case AggregateMin:
+			result = math.Min(result, v)
+		case AggregateMax:
+			result = math.Max(result, v)
+		}
+	}
+	if a.kind == AggregateAvg {
+		result /= float64(len(values.numbers))
+	}
+	return result, true
+}
+
+func defaultFormatter(v interface{}) string {
+	switch n := v.(type) {
+	case float64:
+		return strconv.FormatFloat(n, 'f', 2, 64)
+	case float32:
+		return strconv.FormatFloat(float64(n), 'f', 2, 32)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// numericValue parses the text with the decimal separator of the column unless the text sets its own one
+func (t text) numericValue(separator rune) (float64, bool) {
+	for _, opt := range t.options {
+		if v, ok := opt.(textDecimalSeparator); ok {
+			separator = v.separator
+		}
+	}
+	return parseNumber(t.text, separator)
+}
+
+// parseNumber understands numbers written for humans: "1 200.50", "1,200.50", "1.200,50", "1200,5".
+// The dot or the comma which is not the decimal separator groups the thousands, the number with the groups
+// of other lengths is not a number. Zero separator means it is guessed: the last of the dot and the comma
+// if there are both, the comma followed by three digits groups the thousands
+func parseNumber(s string, separator rune) (float64, bool) {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		}
+		return r
+	}, s)
+	if separator == 0 {
+		separator = guessSeparator(s)
+	}
+	var (
+		group             = ","
+		integer, fraction = s, ""
+	)
+	if separator == ',' {
+		group = "."
+	}
+	if i := strings.LastIndex(s, string(separator)); i >= 0 {
+		integer, fraction = s[:i], s[i+len(string(separator)):]
+		if strings.Contains(fraction, group) {
+			return 0, false
+		}
+		fraction = "." + fraction
+	}
+	groups := strings.Split(integer, group)
+	if len(groups) > 1 {
+		if digits := len(strings.TrimLeft(groups[0], "+-")); digits == 0 || digits > 3 {
+			return 0, false
+		}
+		for _, g := range groups[1:] {
+			if len(g) != 3 {
+				return 0, false
+			}
+		}
+	}
+	v, err := strconv.ParseFloat(strings.Join(groups, "")+fraction, 64)
+	return v, err == nil
+}
+
+// guessSeparator finds out which of the dot and the comma is the decimal separator of the number
+func guessSeparator(s string) rune {
+	var (
+		dot   = strings.LastIndex(s, ".")
+		comma = strings.LastIndex(s, ",")
+	)
+	switch {
+	case dot >= 0 && comma >= 0:
+		if comma > dot {
+			return ','
+		}
+		return '.'
+	case comma >= 0:
+		// "1,200" and "1,200,300" are grouped, "1200,5" is decimal
+		if strings.Count(s, ",") > 1 || len(s)-comma-1 == 3 {
+			return '.'
+		}
+		return ','
+	case strings.Count(s, ".") > 1:
+		// "1.200.300"
+		return ','
+	}
+	return '.'
+}
+
+// cellNumber finds the numeric value of the cell content through the cell wrappers,
+// the separator is the decimal separator of the column (zero if it is not set)
+func cellNumber(d DrawStruct, separator rune) (float64, bool) {
+	if v, ok := cellContent(d).(numericCell); ok {
+		return v.numericValue(separator)
+	}
+	return 0, false
+}
+
+// cellContent unwraps the content of the cell from the padding and the cell style
+func cellContent(d DrawStruct) DrawStruct {
+	for {
+		switch v := d.(type) {
+		case PaddingStruct:
+			d = v.drawContent()
+		case styledCell:
+			d = v.draw
+		default:
+			return d
+		}
+	}
+}
+
+// cellIsEmpty checks whether the cell has any text
+func cellIsEmpty(d DrawStruct) bool {
+	for {
+		switch v := d.(type) {
+		case text:
+			return strings.TrimSpace(v.text) == ""
+		case empty:
+			return true
+		case PaddingStruct:
+			d = v.drawContent()
+		case styledCell:
+			d = v.draw
+		default:
+			return d == nil
+		}
+	}
+}
+
+// collectValues gathers the values of the single-column cells of the rows for the aggregates
+func (t table) collectValues(rows []TableRow) []columnValues {
+	var (
+		values   = make([]columnValues, len(t.columns))
+		occupied = make([]int, len(t.columns))
+	)
+	for _, row := range rows {
+		cells := splitTableRow(occupied, row.getColumnByNum)
+		occupyRows(occupied, cells)
+		for _, cell := range cells {
+			if cell.span > 1 || cellIsEmpty(cell.draw) {
+				continue
+			}
+			values[cell.col].count++
+			if v, ok := cellNumber(cell.draw, t.columns[cell.col].getSeparator()); ok {
+				values[cell.col].numbers = append(values[cell.col].numbers, v)
+			}
+			if f, ok := cellContent(cell.draw).(formattedCell); ok && values[cell.col].formatter == nil {
+				values[cell.col].formatter = f.valueFormatter()
+			}
+		}
+	}
+	return values
+}
+
+// summaryRows passes the cells of the subtotal rows of the groups and of the footer rows to f in the order
+// they are drawn, the aggregates are resolved. The spans of the rows above are closed before every summary row
+func (t table) summaryRows(occupied []int, f func(cells []tableCell, options []CellOption)) {
+	row := func(getColumnStruct func(int) DrawStruct, options []CellOption) {
+		cells := splitTableRow(occupied, getColumnStruct)
+		occupyRows(occupied, cells)
+		f(cells, options)
+	}
+	var subtotals func(rows []TableRow, level int)
+	subtotals = func(rows []TableRow, level int) {
+		if level == len(t.options.groupBy) {
+			return
+		}
+		groupBy := t.options.groupBy[level]
+		groups, _ := groupRows(rows, groupBy.field)
+		for _, group := range groups {
+			subtotals(group.rows, level+1)
+			if groupBy.subtotal == nil {
+				continue
+			}
+			for i := range occupied {
+				occupied[i] = 0
+			}
+			options := append(append([]CellOption{}, t.options.groupStyle...), rowStyle(groupBy.subtotal)...)
+			row(t.resolveAggregates(groupBy.subtotal, t.collectValues(group.rows)), options)
+		}
+	}
+	subtotals(t.rows, 0)
+	if len(t.options.footer) == 0 {
+		return
+	}
+	for i := range occupied {
+		occupied[i] = 0
+	}
+	values := t.collectValues(t.rows)
+	for _, footer := range t.options.footer {
+		options := append(append([]CellOption{}, t.options.footerStyle...), rowStyle(footer)...)
+		row(t.resolveAggregates(footer, values), options)
+	}
+}
+
+// resolveAggregates replaces the aggregates in the cells of the row with the formatted results
+func (t table) resolveAggregates(row TableRow, values []columnValues) func(int) DrawStruct {
+	var (
+		cells    = splitTableRow(make([]int, len(t.columns)), row.getColumnByNum)
+		resolved = make(map[int]DrawStruct, len(cells))
+	)
+	for _, cell := range cells {
+		// the aggregate spanned over several columns is calculated over the last of them
+		var (
+			col    = cell.col + cell.span - 1
+			column = t.columns[col]
+		)
+		resolved[cell.index] = mapCellContent(row.getColumnByNum(cell.index), func(d DrawStruct) DrawStruct {
+			a, ok := d.(aggregate)
+			if !ok {
+				return d
+			}
+			result, ok := a.calculate(values[col])
+			if !ok {
+				return Text("", a.options...)
+			}
+			if f := values[col].formatter; f != nil && column.getFormatter() == nil && a.kind != AggregateCount {
+				// the result is formatted the same way as the bound values of the column
+				return Text(f(result), a.options...)
+			}
+			return Text(column.formatValue(result), a.options...)
+		})
+	}
+	return func(idx int) DrawStruct {
+		if d, ok := resolved[idx]; ok {
+			return d
+		}
+		return row.getColumnByNum(idx)
+	}
+}
+
+// mapCellContent replaces the content of the cell keeping the cell wrappers
+func mapCellContent(d DrawStruct, fn func(DrawStruct) DrawStruct) DrawStruct {
+	switch v := d.(type) {
+	case colSpan:
+		return colSpan{draw: mapCellContent(v.draw, fn), span: v.span}
+	case rowSpan:
+		return rowSpan{draw: mapCellContent(v.draw, fn), span: v.span}
+	case styledCell:
+		return styledCell{draw: mapCellContent(v.draw, fn), options: v.options}
+	default:
+		return fn(d)
+	}
+}
@@ -0,0 +1,107 @@
+package receipt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		in        string
+		separator rune
+		want      float64
+		ok        bool
+	}{
+		{in: "1200", want: 1200, ok: true},
+		{in: "1200.5", want: 1200.5, ok: true},
+		{in: "1200,5", want: 1200.5, ok: true},
+		{in: "1 200.50", want: 1200.5, ok: true},
+		{in: "1,200", want: 1200, ok: true},
+		{in: "1,200.50", want: 1200.5, ok: true},
+		{in: "1.200,50", want: 1200.5, ok: true},
+		{in: "1,200,300", want: 1200300, ok: true},
+		{in: "1.200.300", want: 1200300, ok: true},
+		{in: "-1,200", want: -1200, ok: true},
+		{in: "1,20", want: 1.2, ok: true},
+		{in: "1.200", separator: ',', want: 1200, ok: true},
+		{in: "1,200", separator: ',', want: 1.2, ok: true},
+		{in: "1,200.5", separator: ',', ok: false},
+		{in: "12,00.5", ok: false},
+		{in: "1.2.3", separator: '.', ok: false},
+		{in: "abc", ok: false},
+		{in: "", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := parseNumber(tt.in, tt.separator)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("parseNumber(%q, %q) = %v, %v; want %v, %v", tt.in, tt.separator, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAggregateGroupedThousands(t *testing.T) {
+	tests := []struct {
+		name   string
+		column TableColumn
+		rows   []TableRow
+		want   float64
+	}{
+		{
+			name:   "guessed",
+			column: Column("Amount", 1),
+			rows:   []TableRow{Cols(Text("1,200")), Cols(Text("1.200,50")), Cols(Text("36.00"))},
+			want:   2436.5,
+		},
+		{
+			name:   "column separator",
+			column: Column("Amount", 1, OptionDecimalSeparator(',')),
+			rows:   []TableRow{Cols(Text("1.200")), Cols(Text("1,5")), Cols(Text("36.00"))},
+			want:   1201.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				tbl       = Table([]TableColumn{tt.column}, tt.rows...).(table)
+				result, _ = aggregate{kind: AggregateSum}.calculate(tbl.collectValues(tbl.rows)[0])
+			)
+			if result != tt.want {
+				t.Errorf("the sum is %v, %v expected", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummaryRowsWidth(t *testing.T) {
+	var (
+		columns = []TableColumn{
+			Column("Item", 0, OptionField("name"), SizeAuto()),
+			Column("Sum", 1, OptionField("amount")),
+		}
+		lines = []testOrderLine{
+			{testProduct: testProduct{Name: "Tea"}, Supplier: "Farm", Amount: 5},
+			{testProduct: testProduct{Name: "Egg"}, Supplier: "Farm", Amount: 7},
+		}
+	)
+	tbl, err := TableOf(columns, lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canvas := NewTextCanvas(32, Millimeters(72))
+	canvas.Write(tbl.WithOptions(
+		OptionGroupBy("supplier", Cols(Text("Subtotal"), FooterSum())),
+		OptionFooter(Cols(Text("Total"), FooterSum())),
+	))
+	out := canvas.String()
+	for _, label := range []string{"Subtotal", "Total"} {
+		found := false
+		for _, line := range strings.Split(out, "\n") {
+			if strings.Contains(line, label) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%q is not on one line:\n%s", label, out)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package receipt
+
+import (
+	"reflect"
+)
+
+type (
+	tableGroupBy struct {
+		field    string
+		subtotal TableRow
+	}
+	tableGroupStyle struct {
+		options []CellOption
+	}
+	// recordRow is implemented by the rows bound to the records, see BindRows
+	recordRow interface {
+		recordValue(field string) (value interface{}, format string, ok bool)
+	}
+	rowGroup struct {
+		key  interface{}
+		rows []TableRow
+	}
+)
+
+// OptionGroupBy groups the rows bound to the records (see TableOf) by the value of the field.
+// Every group starts with the header row holding the value and ends with the subtotal row,
+// the aggregates of which are calculated over the rows of the group, the subtotal can be nil.
+// The groups follow in the order of their first rows; each call adds the level nested in the previous ones.
+// The aggregates of the footer are still calculated over all the rows, so the footer becomes the grand total
+//
+//	OptionGroupBy("supplier", Cols(ColSpan(Text("Subtotal"), 3), FooterSum()))
+func OptionGroupBy(field string, subtotal TableRow) TableOption {
+	return tableGroupBy{
+		field:    field,
+		subtotal: subtotal,
+	}
+}
+
+// OptionGroupStyle sets the look of the group header and subtotal rows
+func OptionGroupStyle(options ...CellOption) TableOption {
+	return tableGroupStyle{options: options}
+}
+
+func (_ tableGroupBy) tableOptInt() int {
+	return 0
+}
+
+func (_ tableGroupStyle) tableOptInt() int {
+	return 0
+}
+
+func (r boundRow) recordValue(field string) (interface{}, string, bool) {
+	value, format, err := recordField(r.record, field)
+	if err != nil {
+		return nil, "", false
+	}
+	return value, format, true
+}
+
+// groupRows splits the rows into the groups by the value of the field keeping the order of the rows,
+// the rows which are not bound to any record fall into the group with the nil key
+func groupRows(rows []TableRow, field string) (groups []rowGroup, format string) {
+	var index = make(map[interface{}]int)
+	for _, row := range rows {
+		var key interface{}
+		if r, ok := row.(recordRow); ok {
+			if value, f, ok := r.recordValue(field); ok {
+				key, format = value, f
+			}
+		}
+		// the keys of incomparable types can not be used in the map, they are grouped by their text
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			key = defaultFormatter(key)
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, rowGroup{key: key})
+		}
+		groups[i].rows = append(groups[i].rows, row)
+	}
+	return groups, format
+}
+
+// groupCaption formats the key of the group the same way as the column bound to the field does
+func (t table) groupCaption(field, format string, key interface{}) string {
+	for _, col := range t.columns {
+		if col.getField() == field {
+			return boundFormatter(col, format)(key)
+		}
+	}
+	if f, ok := lookupFormatter(format); ok && format != "" {
+		return f(key)
+	}
+	return defaultFormatter(key)
+}
+
+// layoutGroups lists the rows grouped at the level and the nested levels, index counts the body rows for zebra striping
+func (t table) layoutGroups(rows []TableRow, level int, index *int) []layoutRow {
+	var result []layoutRow
+	if level == len(t.options.groupBy) {
+		for _, row := range rows {
+			result = append(result, layoutRow{getColumnStruct: row.getColumnByNum, options: t.rowOptions(*index, row)})
+			*index++
+		}
+		return result
+	}
+	var (
+		groupBy        = t.options.groupBy[level]
+		groups, format = groupRows(rows, groupBy.field)
+	)
+	for _, group := range groups {
+		// the cells spanning several rows never cross the border of the group
+		header := Cols(ColSpan(Text(t.groupCaption(groupBy.field, format, group.key)), len(t.columns)))
+		result = append(result, layoutRow{getColumnStruct: header.getColumnByNum, options: t.options.groupStyle, finish: true})
+		result = append(result, t.layoutGroups(group.rows, level+1, index)...)
+		if groupBy.subtotal == nil {
+			continue
+		}
+		options := append(append([]CellOption{}, t.options.groupStyle...), rowStyle(groupBy.subtotal)...)
+		values := t.collectValues(group.rows)
+		result = append(result, layoutRow{getColumnStruct: t.resolveAggregates(groupBy.subtotal, values), options: options, finish: true})
+	}
+	return result
+}
@@ -0,0 +1,179 @@
+package receipt
+
+import (
+	"sort"
+)
+
+type (
+	// HeaderGroup is the caption which groups several adjacent columns in the table header, see OptionHeaderGroups
+	HeaderGroup interface {
+		groupCaption() string
+		groupSpan() int
+	}
+	headerGroup struct {
+		caption string
+		span    int
+	}
+	tableNoHeader    struct{}
+	tableHeaderStyle struct {
+		options []CellOption
+	}
+	tableHeaderGroups struct {
+		groups []HeaderGroup
+	}
+	headerCell struct {
+		col     int
+		span    int
+		rowSpan int
+		caption string
+	}
+)
+
+// Group makes the header caption over the span of adjacent columns. A group with an empty caption
+// does not make a cell: the captions below it extend upwards to take its place
+func Group(caption string, span int) HeaderGroup {
+	return headerGroup{
+		caption: caption,
+		span:    span,
+	}
+}
+
+func (g headerGroup) groupCaption() string {
+	return g.caption
+}
+
+func (g headerGroup) groupSpan() int {
+	return g.span
+}
+
+// OptionNoHeader suppresses the header of the table, e.g. for the table of totals
+func OptionNoHeader() TableOption {
+	return tableNoHeader{}
+}
+
+// OptionHeaderStyle sets the look of the header cells: font, background, alignment and borders.
+// By default the captions are centered and drawn with the font of the column
+func OptionHeaderStyle(options ...CellOption) TableOption {
+	return tableHeaderStyle{options: options}
+}
+
+// OptionHeaderGroups adds the level of grouped captions to the table header.
+// Each call adds the level below the previous ones and above the column captions, e.g.
+//
+//	OptionHeaderGroups(Group("", 2), Group("Retail", 2), Group("Wholesale", 2))
+func OptionHeaderGroups(groups ...HeaderGroup) TableOption {
+	return tableHeaderGroups{groups: groups}
+}
+
+func (_ tableNoHeader) tableOptInt() int {
+	return 0
+}
+
+func (_ tableHeaderStyle) tableOptInt() int {
+	return 0
+}
+
+func (_ tableHeaderGroups) tableOptInt() int {
+	return 0
+}
+
+// headerOptions returns the options applied to every cell of the header
+func (t table) headerOptions() []CellOption {
+	return append([]CellOption{
+		OptionAlignment(AlignCenter),
+		OptionCentered(),
+	}, t.options.headerStyle...)
+}
+
+// headerRows builds the rows of the header from the column groups and the column captions.
+// Columns under a group with an empty caption are open until a caption closes them,
+// that caption spans all the rows of the levels the columns were open for
+func (t table) headerRows() []TableRow {
+	if t.options.noHeader {
+		return nil
+	}
+	var (
+		levels    = len(t.options.headerGroups) + 1
+		openSince = make([]int, len(t.columns))
+		cells     = make([][]headerCell, levels)
+	)
+	for i := range openSince {
+		openSince[i] = -1
+	}
+	closeGroup := func(level, col, span int, caption string) {
+		start := openSince[col]
+		for c := col; c < col+span; c++ {
+			if openSince[c] != start {
+				start = -1
+			}
+		}
+		if start < 0 {
+			// the columns were opened at different levels, so each of them is closed by an empty cell
+			for c := col; c < col+span; c++ {
+				if openSince[c] >= 0 {
+					cells[openSince[c]] = append(cells[openSince[c]], headerCell{
+						col:     c,
+						span:    1,
+						rowSpan: level - openSince[c],
+					})
+				}
+			}
+			start = level
+		}
+		cells[start] = append(cells[start], headerCell{
+			col:     col,
+			span:    span,
+			rowSpan: level - start + 1,
+			caption: caption,
+		})
+		for c := col; c < col+span; c++ {
+			openSince[c] = -1
+		}
+	}
+	for level, groups := range t.options.headerGroups {
+		col := 0
+		for _, g := range groups {
+			span := g.groupSpan()
+			if span < 1 {
+				span = 1
+			}
+			if col+span > len(t.columns) {
+				span = len(t.columns) - col
+			}
+			if span < 1 {
+				break
+			}
+			if g.groupCaption() != "" {
+				closeGroup(level, col, span, g.groupCaption())
+			} else {
+				for c := col; c < col+span; c++ {
+					if openSince[c] < 0 {
+						openSince[c] = level
+					}
+				}
+			}
+			col += span
+		}
+		for c := col; c < len(t.columns); c++ {
+			// the columns which are not grouped stay open
+			if openSince[c] < 0 {
+				openSince[c] = level
+			}
+		}
+	}
+	for i, col := range t.columns {
+		closeGroup(levels-1, i, 1, col.getCaption())
+	}
+	rows := make([]TableRow, 0, levels)
+	for _, level := range cells {
+		sort.Slice(level, func(i, j int) bool {
+			return level[i].col < level[j].col
+		})
+		row := make([]DrawStruct, 0, len(level))
+		for _, cell := range level {
+			row = append(row, RowSpan(ColSpan(Text(cell.caption), cell.span), cell.rowSpan))
+		}
+		rows = append(rows, Cols(row...))
+	}
+	return rows
+}
@@ -5,7 +5,6 @@ import (
 	"golang.org/x/image/font"
 	"image"
 	"image/draw"
-	"math"
 )
 
 type (
@@ -16,12 +15,20 @@ type (
 		getColumnByNum(int) DrawStruct
 	}
 	TableColumn interface {
-		calculateWidth(tableWidth int) int
+		getSize() Size
 		getTextOptions() []TextOption
 		getCaption() string
 		getPen() pen
-		makeFontDrawer(img draw.Image) *font.Drawer
-		extractDrawStruct(DrawStruct) (DrawStruct, func(DrawStruct) DrawStruct)
+		formatValue(v interface{}) string
+		getFormatter() Formatter
+		getField() string
+		getSeparator() rune
+		makeFontDrawer(img draw.Image, dpi float64) *font.Drawer
+		extractDrawStruct(DrawStruct, ...TextOption) (DrawStruct, func(DrawStruct) DrawStruct)
+	}
+	// textContainer passes the default text options to the texts it contains
+	textContainer interface {
+		defaultOptions(options ...TextOption) DrawStruct
 	}
 	ColumnSpan interface {
 		spanCount() int
@@ -34,24 +41,75 @@ type (
 		font      *truetype.Font
 		fontSize  float64
 		usePen    pen
-		pie       float64
+		size      Size
+		formatter Formatter
+		field     string
+		separator rune
 	}
 	table struct {
 		columns []TableColumn
 		rows    []TableRow
+		options tableOptions
 	}
 	colSpan struct {
 		draw DrawStruct
 		span int
 	}
+	RowSpanning interface {
+		rowSpanCount() int
+		drawContent() DrawStruct
+	}
+	rowSpan struct {
+		draw DrawStruct
+		span int
+	}
+	tableCell struct {
+		col     int
+		span    int
+		rowSpan int
+		index   int
+		draw    DrawStruct
+		options []CellOption
+	}
+
+	// tableTarget draws the cells of the table on Canvas or TextCanvas
+	tableTarget interface {
+		writeCell(column TableColumn, cell tableCell, rect image.Rectangle, style cellStyle) int
+		measureCell(column TableColumn, cell tableCell, rect image.Rectangle, style cellStyle) int
+		fillCell(rect image.Rectangle, style cellStyle)
+		drawBorder(rect image.Rectangle, style cellStyle)
+	}
+	tableLayout struct {
+		table    table
+		bounds   []int
+		occupied []int
+		spans    []openSpan
+		decimals []decimalColumn
+	}
+	openSpan struct {
+		cell   tableCell
+		style  cellStyle
+		rect   image.Rectangle
+		bottom int
+		rows   int
+	}
+	pixelTable struct {
+		canvas Canvas
+	}
 )
 
+// Column describes the table column, its caption and the width as a fraction (pie) of the table width.
+// Pass SizeAuto as an option to calculate the width from the content or SizeFixed to set the absolute width instead
 func Column(caption string, pie float64, options ...ColumnOption) TableColumn {
 	var (
 		font      *truetype.Font
 		fontSize  float64 = defaultFontSize
 		usePen            = defaultPen
-		alignment         = cellAlignment{
+		size      Size    = sizeFraction{pie: pie}
+		formatter Formatter
+		field     string
+		separator rune
+		alignment = cellAlignment{
 			hAlign:    AlignLeft,
 			vCentered: false,
 		}
@@ -68,6 +126,18 @@ func Column(caption string, pie float64, options ...ColumnOption) TableColumn {
 		if _, ok := opt.(textCentered); ok {
 			alignment.vCentered = true
 		}
+		if s, ok := opt.(Size); ok {
+			size = s
+		}
+		if f, ok := opt.(columnFormatter); ok {
+			formatter = f.formatter
+		}
+		if f, ok := opt.(columnField); ok {
+			field = f.name
+		}
+		if d, ok := opt.(textDecimalSeparator); ok {
+			separator = d.separator
+		}
 	}
 	if font == nil {
 		font = getDefaultFont()
@@ -79,7 +149,10 @@ func Column(caption string, pie float64, options ...ColumnOption) TableColumn {
 		font:      font,
 		fontSize:  fontSize,
 		usePen:    usePen,
-		pie:       pie,
+		size:      size,
+		formatter: formatter,
+		field:     field,
+		separator: separator,
 	}
 }
 
@@ -94,6 +167,35 @@ func (c colSpan) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
 	return c.draw.WriteTo(canvas, rect)
 }
 
+// RowSpan merges the cell with the cells below it, the content is drawn once and the border
+// is drawn around the whole merged area. The rows below must not contain the cells for the spanned columns
+func RowSpan(d DrawStruct, span int) DrawStruct {
+	return rowSpan{
+		draw: d,
+		span: span,
+	}
+}
+
+func (r rowSpan) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	return r.draw.WriteTo(canvas, rect)
+}
+
+func (r rowSpan) measureContent(m measurer) (minWidth, maxWidth int) {
+	return measureStruct(m, r.draw)
+}
+
+func (r rowSpan) rowSpanCount() int {
+	return r.span
+}
+
+func (r rowSpan) drawContent() DrawStruct {
+	return r.draw
+}
+
+func (c colSpan) measureContent(m measurer) (minWidth, maxWidth int) {
+	return measureStruct(m, c.draw)
+}
+
 func (c colSpan) spanCount() int {
 	return c.span
 }
@@ -103,22 +205,27 @@ func (c colSpan) drawContent() DrawStruct {
 }
 
 func (c tableColumn) getTextOptions() []TextOption {
+	var options []TextOption
 	if c.centered {
-		return []TextOption{
+		options = []TextOption{
 			OptionCentered(),
 			OptionFont(c.font, c.fontSize, c.usePen),
 			OptionAlignment(c.alignment.alignment),
 		}
 	} else {
-		return []TextOption{
+		options = []TextOption{
 			OptionFont(c.font, c.fontSize, c.usePen),
 			OptionAlignment(c.alignment.alignment),
 		}
 	}
+	if c.separator != 0 {
+		options = append(options, OptionDecimalSeparator(c.separator))
+	}
+	return options
 }
 
-func (t tableColumn) calculateWidth(tableWidth int) int {
-	return int(math.Round(float64(tableWidth) * t.pie))
+func (t tableColumn) getSize() Size {
+	return t.size
 }
 
 func (t tableColumn) getCaption() string {
@@ -129,18 +236,42 @@ func (t tableColumn) getPen() pen {
 	return t.usePen
 }
 
-func (t tableColumn) makeFontDrawer(img draw.Image) *font.Drawer {
-	return makeFontDrawer(img, t.font, t.usePen.color, t.fontSize)
+func (t tableColumn) formatValue(v interface{}) string {
+	if t.formatter == nil {
+		return defaultFormatter(v)
+	}
+	return t.formatter(v)
+}
+
+func (t tableColumn) getFormatter() Formatter {
+	return t.formatter
+}
+
+func (t tableColumn) getField() string {
+	return t.field
+}
+
+// getSeparator returns the decimal separator set by the column options, zero if it is not set
+func (t tableColumn) getSeparator() rune {
+	return t.separator
+}
+
+func (t tableColumn) makeFontDrawer(img draw.Image, dpi float64) *font.Drawer {
+	return makeFontDrawer(img, t.font, t.usePen.color, t.fontSize, dpi)
 }
 
-func Table(columns []TableColumn, data ...TableRow) DrawStruct {
+// Table draws the table with the header made of the column captions and the rows of data,
+// use WithOptions to customize the look of the whole table
+func Table(columns []TableColumn, data ...TableRow) DrawTable {
 	return table{
 		columns: columns,
 		rows:    data,
 	}
 }
 
-func (t tableColumn) extractDrawStruct(draw DrawStruct) (DrawStruct, func(DrawStruct) DrawStruct) {
+// extractDrawStruct applies the default options of the column and the cell padding to the content of the cell,
+// the text options of the row and the cell take precedence over the column options
+func (t tableColumn) extractDrawStruct(draw DrawStruct, options ...TextOption) (DrawStruct, func(DrawStruct) DrawStruct) {
 	var padFunc = func(d DrawStruct) DrawStruct {
 		// default cell padding
 		return Padding4(millimeters(cellPadding), d)
@@ -152,7 +283,15 @@ func (t tableColumn) extractDrawStruct(draw DrawStruct) (DrawStruct, func(DrawSt
 			// apply padding to cell
 			padFunc = v.getPaddingFnc()
 		case DrawText:
-			draw = v.defaultOptions(t.getTextOptions()...)
+			draw = v.defaultOptions(mergeTextOptions(options, t.getTextOptions())...)
+			return draw, padFunc
+		case table:
+			// the nested table is drawn without the padding, its outer border collapses with the border of the cell
+			return draw, func(d DrawStruct) DrawStruct {
+				return d
+			}
+		case textContainer:
+			draw = v.defaultOptions(mergeTextOptions(options, t.getTextOptions())...)
 			return draw, padFunc
 		default:
 			return draw, padFunc
@@ -160,101 +299,398 @@ func (t tableColumn) extractDrawStruct(draw DrawStruct) (DrawStruct, func(DrawSt
 	}
 }
 
-func writeTableRow(
-	t table,
-	tableWidth int,
-	left, top int,
-	canvas Canvas,
-	getColumnStruct func(int) DrawStruct,
-) int {
+// splitTableRow breaks the row into cells, each cell knows the index of the first
+// column it occupies and how many columns and rows it spans.
+// The columns occupied by the cells spanned from the rows above are skipped
+func splitTableRow(occupied []int, getColumnStruct func(int) DrawStruct) []tableCell {
 	var (
-		spanned   int
-		colObjIdx int
-		colWidth  int
-		headRects = make([]image.Rectangle, 0, len(t.columns))
-		bottom    = top + int(mmToPix(2))
+		columns = len(occupied)
+		cells   = make([]tableCell, 0, columns)
 	)
-	for _, col := range t.columns {
-		if spanned < 2 {
-			colWidth += col.calculateWidth(tableWidth)
-			colRect := image.Rect(left, top, colWidth+left, bottom)
-			draw := getColumnStruct(colObjIdx)
-			if d, ok := draw.(ColumnSpan); ok {
-				draw = d.drawContent()
-				if spanned < 1 {
-					spanned = d.spanCount() - 1
-					continue
-				} else {
-					spanned = 0
-				}
+	for col, idx := 0, 0; col < columns; idx++ {
+		for col < columns && occupied[col] > 0 {
+			col++
+		}
+		if col == columns {
+			break
+		}
+		cell := unwrapTableCell(getColumnStruct(idx))
+		cell.col = col
+		cell.index = idx
+		for n := 1; n < cell.span; n++ {
+			if col+n == columns || occupied[col+n] > 0 {
+				cell.span = n
+				break
+			}
+		}
+		cells = append(cells, cell)
+		col += cell.span
+	}
+	return cells
+}
+
+// unwrapTableCell extracts the content of the cell from ColSpan and RowSpan wrappers
+func unwrapTableCell(draw DrawStruct) tableCell {
+	cell := tableCell{
+		span:    1,
+		rowSpan: 1,
+	}
+	for {
+		switch v := draw.(type) {
+		case ColumnSpan:
+			draw = v.drawContent()
+			if v.spanCount() > 1 {
+				cell.span = v.spanCount()
 			}
-			draw, padFunc := col.extractDrawStruct(draw)
-			end := padFunc(draw).WriteTo(canvas, colRect)
-			left += colWidth
-			if end.Y > bottom {
-				bottom = end.Y
+		case RowSpanning:
+			draw = v.drawContent()
+			if v.rowSpanCount() > 1 {
+				cell.rowSpan = v.rowSpanCount()
 			}
-			headRects = append(headRects, colRect)
-			colWidth = 0
-			colObjIdx++
-		} else {
-			colWidth += col.calculateWidth(tableWidth)
-			spanned--
+		case styledCell:
+			draw = v.draw
+			cell.options = append(cell.options, v.options...)
+		default:
+			cell.draw = draw
+			return cell
 		}
 	}
-	for i, rect := range headRects {
-		rect.Max.Y = bottom
-		drawRect(canvas.img, rect, t.columns[i].getPen())
+}
+
+// occupyRows marks the columns covered by the cells spanned down from the current row
+func occupyRows(occupied []int, cells []tableCell) {
+	for col := range occupied {
+		if occupied[col] > 0 {
+			occupied[col]--
+		}
+	}
+	for _, cell := range cells {
+		for col := cell.col; col < cell.col+cell.span; col++ {
+			occupied[col] = cell.rowSpan - 1
+		}
+	}
+}
+
+func newTableLayout(t table, bounds []int) *tableLayout {
+	return &tableLayout{
+		table:    t,
+		bounds:   bounds,
+		occupied: make([]int, len(t.columns)),
 	}
-	return bottom
 }
 
-func writeTableHeader(
-	t table,
-	canvas Canvas,
-	rect image.Rectangle,
+// writeRow draws the cells of the row, the row is at least minHeight high.
+// If some cells of the row have a background, the row is measured before it is drawn.
+// The cells which span several rows are measured at the first row, but they are drawn
+// when the last spanned row is completed and the height of the merged area is known
+func (l *tableLayout) writeRow(
+	target tableTarget,
+	top, minHeight int,
+	getColumnStruct func(int) DrawStruct,
+	rowOptions []CellOption,
 ) int {
 	var (
-		tableWidth  = rect.Dx()
-		left        = rect.Min.X
-		top         = rect.Min.Y
-		bottom      = top
-		cellPadding = int(mmToPix(cellPadding))
-		headRects   = make([]image.Rectangle, 0, len(t.columns))
+		cells   = splitTableRow(l.occupied, getColumnStruct)
+		styles  = make([]cellStyle, len(cells))
+		rects   = make([]image.Rectangle, len(cells))
+		bottom  = top + minHeight
+		measure = false
 	)
-	for _, col := range t.columns {
-		colWidth := col.calculateWidth(tableWidth)
-		colRect := image.Rect(left, top, colWidth+left, top+int(mmToPix(5)))
-		fontDrawer := col.makeFontDrawer(canvas.img)
-		b := fillTextIntoRect(
-			fontDrawer,
-			col.getCaption(),
-			colRect.Inset(cellPadding),
-			cellAlignment{
-				hAlign:    AlignCenter,
-				vCentered: true,
-			},
+	for i, cell := range cells {
+		styles[i] = l.cellStyle(cell, rowOptions)
+		if styles[i].background != nil {
+			measure = true
+		}
+	}
+	for i, cell := range cells {
+		var (
+			column = l.table.columns[cell.col]
+			end    int
 		)
-		if b+cellPadding > bottom {
-			bottom = b + cellPadding
+		rects[i] = image.Rect(l.bounds[cell.col], top, l.bounds[cell.col+cell.span], bottom)
+		if measure || cell.rowSpan > 1 {
+			end = target.measureCell(column, cell, rects[i], styles[i])
+		} else {
+			end = target.writeCell(column, cell, rects[i], styles[i])
+		}
+		if cell.rowSpan > 1 {
+			l.spans = append(l.spans, openSpan{
+				cell:   cell,
+				style:  styles[i],
+				rect:   rects[i],
+				bottom: end,
+				rows:   cell.rowSpan,
+			})
+			continue
+		}
+		if end > bottom {
+			bottom = end
+		}
+	}
+	for _, span := range l.spans {
+		// the last spanned row grows to fit the content of the spanned cell
+		if span.rows == 1 && span.bottom > bottom {
+			bottom = span.bottom
+		}
+	}
+	if measure {
+		for i, cell := range cells {
+			if cell.rowSpan > 1 {
+				continue
+			}
+			cellRect := rects[i]
+			cellRect.Max.Y = bottom
+			target.fillCell(cellRect, styles[i])
+			target.writeCell(l.table.columns[cell.col], cell, rects[i], styles[i])
+		}
+	}
+	occupyRows(l.occupied, cells)
+	closed := l.closeSpans(target, bottom, false)
+	for i, cell := range cells {
+		if cell.rowSpan > 1 {
+			continue
 		}
-		left += colWidth
-		headRects = append(headRects, colRect)
+		rects[i].Max.Y = bottom
+		target.drawBorder(rects[i], styles[i])
 	}
-	for i, rect := range headRects {
-		rect.Max.Y = bottom
-		drawRect(canvas.img, rect, t.columns[i].getPen())
+	for _, span := range closed {
+		target.drawBorder(span.rect, span.style)
 	}
 	return bottom
 }
 
-func (t table) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
-	bottom := writeTableHeader(t, canvas, rect)
-	for _, row := range t.rows {
-		bottom = writeTableRow(t, rect.Dx(), rect.Min.X, bottom, canvas, row.getColumnByNum)
+// closeSpans draws the content of the spanned cells which end at the current row and returns them,
+// so that their borders could be drawn along with the borders of the row
+func (l *tableLayout) closeSpans(target tableTarget, bottom int, all bool) []openSpan {
+	var (
+		open   = make([]openSpan, 0, len(l.spans))
+		closed []openSpan
+	)
+	for _, span := range l.spans {
+		span.rows--
+		if span.rows > 0 && !all {
+			open = append(open, span)
+			continue
+		}
+		span.rect.Max.Y = bottom
+		target.fillCell(span.rect, span.style)
+		target.writeCell(l.table.columns[span.cell.col], span.cell, span.rect, span.style)
+		closed = append(closed, span)
+	}
+	l.spans = open
+	return closed
+}
+
+// finish closes the cells which span beyond the last row of the table
+func (l *tableLayout) finish(target tableTarget, bottom int) int {
+	for _, span := range l.spans {
+		if span.bottom > bottom {
+			bottom = span.bottom
+		}
+	}
+	for _, span := range l.closeSpans(target, bottom, true) {
+		target.drawBorder(span.rect, span.style)
+	}
+	for i := range l.occupied {
+		l.occupied[i] = 0
+	}
+	return bottom
+}
+
+func (p pixelTable) writeCell(column TableColumn, cell tableCell, rect image.Rectangle, style cellStyle) int {
+	draw, padFunc := column.extractDrawStruct(cell.draw, style.text...)
+	return padFunc(draw).WriteTo(p.canvas, rect).Y
+}
+
+func (p pixelTable) measureCell(column TableColumn, cell tableCell, rect image.Rectangle, style cellStyle) int {
+	return pixelTable{canvas: p.canvas.measure()}.writeCell(column, cell, rect, style)
+}
+
+func (p pixelTable) fillCell(rect image.Rectangle, style cellStyle) {
+	if style.background != nil {
+		draw.Draw(p.canvas.img, rect, image.NewUniform(style.background), image.Point{}, draw.Over)
+	}
+}
+
+func (p pixelTable) drawBorder(rect image.Rectangle, style cellStyle) {
+	drawRectSides(p.canvas, rect, style.usePen, style.sides)
+}
+
+// columnWidths measures the content of the columns and distributes the table width between them,
+// the border is the width reserved for the column border in addition to the content
+func (t table) columnWidths(m measurer, tableWidth, border int) []int {
+	return distributeSizes(tableWidth, t.columnTracks(m, border, false))
+}
+
+// measureContent makes the nested table fit into the auto-sized column of the outer table
+func (t table) measureContent(m measurer) (minWidth, maxWidth int) {
+	border := m.borderWidth()
+	for _, track := range t.columnTracks(m, border, true) {
+		minWidth += track.minContent
+		maxWidth += track.maxContent
+	}
+	// the closing border of the last column
+	return minWidth + border, maxWidth + border
+}
+
+// columnTracks measures the content of the auto-sized columns (and of the fractions as well if all is set)
+func (t table) columnTracks(m measurer, border int, all bool) []sizeTrack {
+	if len(t.columns) == 0 {
+		// there is no column to take the text options of the header from
+		return nil
+	}
+	var (
+		tracks     = make([]sizeTrack, len(t.columns))
+		headerText = newTableLayout(t, nil).cellStyle(tableCell{}, t.headerOptions()).text
+		measured   = func(col int) bool {
+			switch tracks[col].size.(type) {
+			case sizeAuto:
+				return true
+			case sizeFraction:
+				return all
+			}
+			return false
+		}
+	)
+	for i, col := range t.columns {
+		tracks[i].size = col.getSize()
+		if f, ok := tracks[i].size.(sizeFixed); ok {
+			tracks[i].minContent = m.toWidth(f.width)
+			tracks[i].maxContent = tracks[i].minContent
+		}
+		if !measured(i) {
+			continue
+		}
+		draw, padFunc := col.extractDrawStruct(Text(col.getCaption()), headerText...)
+		tracks[i].minContent, tracks[i].maxContent = measureStruct(m, padFunc(draw))
+	}
+	var (
+		occupied     = make([]int, len(t.columns))
+		layout       = newTableLayout(t, nil)
+		measureCells = func(cells []tableCell, rowOptions []CellOption) {
+			for _, cell := range cells {
+				if !measured(cell.col) || cell.span > 1 {
+					continue
+				}
+				// the fonts of the row and of the cell change the width of the text
+				style := layout.cellStyle(cell, rowOptions)
+				draw, padFunc := t.columns[cell.col].extractDrawStruct(cell.draw, style.text...)
+				minWidth, maxWidth := measureStruct(m, padFunc(draw))
+				if minWidth > tracks[cell.col].minContent {
+					tracks[cell.col].minContent = minWidth
+				}
+				if maxWidth > tracks[cell.col].maxContent {
+					tracks[cell.col].maxContent = maxWidth
+				}
+			}
+		}
+	)
+	for i, row := range t.rows {
+		cells := splitTableRow(occupied, row.getColumnByNum)
+		occupyRows(occupied, cells)
+		measureCells(cells, t.rowOptions(i, row))
+	}
+	t.summaryRows(occupied, measureCells)
+	for i, column := range t.decimalColumns(m) {
+		if !measured(i) || !column.aligned {
+			continue
+		}
+		// the lined up numbers are wider than any of them
+		if column.width() > tracks[i].minContent {
+			tracks[i].minContent = column.width()
+		}
+		if column.width() > tracks[i].maxContent {
+			tracks[i].maxContent = column.width()
+		}
+	}
+	for i := range tracks {
+		if _, ok := tracks[i].size.(sizeFixed); ok {
+			// the fixed width already includes the border
+			continue
+		}
+		tracks[i].minContent += border
+		tracks[i].maxContent += border
+	}
+	return tracks
+}
+
+// layoutRow is the row of the table in the order of drawing: the header, the body, the group and the footer rows
+type layoutRow struct {
+	getColumnStruct func(int) DrawStruct
+	options         []CellOption
+	header          bool
+	// finish closes the spanned cells of the rows above before the row is drawn
+	finish bool
+}
+
+// layoutRows lists the header, the body and the footer rows of the table
+func (t table) layoutRows() []layoutRow {
+	var (
+		rows          []layoutRow
+		headerOptions = t.headerOptions()
+	)
+	for _, row := range t.headerRows() {
+		rows = append(rows, layoutRow{getColumnStruct: row.getColumnByNum, options: headerOptions, header: true})
 	}
+	if len(t.options.groupBy) > 0 {
+		var index int
+		rows = append(rows, t.layoutGroups(t.rows, 0, &index)...)
+	} else {
+		for i, row := range t.rows {
+			rows = append(rows, layoutRow{getColumnStruct: row.getColumnByNum, options: t.rowOptions(i, row)})
+		}
+	}
+	if len(t.options.footer) == 0 {
+		return rows
+	}
+	values := t.collectValues(t.rows)
+	for i, row := range t.options.footer {
+		options := append(append([]CellOption{}, t.options.footerStyle...), rowStyle(row)...)
+		rows = append(rows, layoutRow{getColumnStruct: t.resolveAggregates(row, values), options: options, finish: i == 0})
+	}
+	return rows
+}
+
+// writeRows draws the rows, the rows are at least of the specified heights
+func (t table) writeRows(target tableTarget, layout *tableLayout, rows []layoutRow, top, headerHeight, rowHeight int) int {
+	bottom := top
+	for _, row := range rows {
+		if row.finish {
+			bottom = layout.finish(target, bottom)
+		}
+		height := rowHeight
+		if row.header {
+			height = headerHeight
+		}
+		bottom = layout.writeRow(target, bottom, height, row.getColumnStruct, row.options)
+	}
+	return layout.finish(target, bottom)
+}
+
+func (t table) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	layout := newTableLayout(t, t.columnBounds(canvas, rect))
+	layout.decimals = t.decimalColumns(canvas)
 	return image.Point{
 		X: rect.Min.X,
-		Y: bottom,
+		Y: t.writePixelRows(canvas, layout, t.layoutRows(), rect.Min.Y),
+	}
+}
+
+// columnBounds returns the left sides of the columns and the right side of the last one
+func (t table) columnBounds(canvas Canvas, rect image.Rectangle) []int {
+	var (
+		widths = t.columnWidths(canvas, rect.Dx(), canvas.borderWidth())
+		bounds = make([]int, 0, len(widths)+1)
+		left   = rect.Min.X
+	)
+	bounds = append(bounds, left)
+	for _, w := range widths {
+		left += w
+		bounds = append(bounds, left)
 	}
+	return bounds
+}
+
+func (t table) writePixelRows(canvas Canvas, layout *tableLayout, rows []layoutRow, top int) int {
+	return t.writeRows(pixelTable{canvas: canvas}, layout, rows, top, int(mmToPix(5, canvas.dpi)), int(mmToPix(2, canvas.dpi)))
 }
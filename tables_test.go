@@ -0,0 +1,50 @@
+package receipt
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func testCanvas(width, height int) Canvas {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	return NewCanvas(img, img.Rect)
+}
+
+func TestColumnTracksCellFont(t *testing.T) {
+	var (
+		canvas  = testCanvas(2000, 500)
+		large   = OptionFont(getDefaultFont(), 30, defaultPen)
+		_, want = Text("WIDE TEXT", large).(text).measureContent(canvas)
+		columns = []TableColumn{Column("A", 0, SizeAuto()), Column("B", 1)}
+	)
+	tests := []struct {
+		name string
+		row  TableRow
+	}{
+		{name: "cell", row: Cols(Cell(Text("WIDE TEXT"), large), Text("x"))},
+		{name: "row", row: StyleRow(Cols(Text("WIDE TEXT"), Text("x")), large)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracks := Table(columns, tt.row).(table).columnTracks(canvas, 0, false)
+			if tracks[0].maxContent < want {
+				t.Errorf("the auto column is %d pixels wide, %d expected at least", tracks[0].maxContent, want)
+			}
+		})
+	}
+}
+
+func TestTableWithoutColumns(t *testing.T) {
+	d := Lines(Table(nil), Text("after"))
+	canvas := testCanvas(400, 200)
+	canvas.Write(d)
+	txt := NewTextCanvas(32, Millimeters(72))
+	txt.Write(d)
+	if !strings.Contains(txt.String(), "after") {
+		t.Errorf("the text after the table is lost:\n%s", txt)
+	}
+	if minWidth, maxWidth := measureStruct(canvas, Table(nil)); minWidth != 0 || maxWidth != 0 {
+		t.Errorf("the table without columns is %d..%d pixels wide", minWidth, maxWidth)
+	}
+}
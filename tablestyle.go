@@ -0,0 +1,285 @@
+package receipt
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+)
+
+type (
+	// CellOption changes the look of a table cell or a whole row:
+	//  OptionBackground, OptionBorders, OptionBorderPen, OptionFont, OptionAlignment, OptionCentered,
+	//  OptionDecimalSeparator
+	CellOption interface {
+		cellOptInt() int
+	}
+	// TableOption changes the look of the whole table:
+	//  OptionZebra, OptionHideInnerVertical, OptionNoHeader, OptionHeaderStyle, OptionHeaderGroups,
+	//  OptionFooter, OptionFooterStyle, OptionGroupBy, OptionGroupStyle
+	TableOption interface {
+		tableOptInt() int
+	}
+	// DrawTable is the table which can be customized with table options
+	DrawTable interface {
+		WriteTo(Canvas, image.Rectangle) image.Point
+		WithOptions(options ...TableOption) DrawTable
+	}
+	// RowStyler is the table row which has its own style, see StyleRow
+	RowStyler interface {
+		getColumnByNum(int) DrawStruct
+		rowOptions() []CellOption
+	}
+	// BorderSide is a set of cell sides:
+	//  BorderLeft, BorderTop, BorderRight, BorderBottom, BorderAll, BorderNone
+	BorderSide int
+
+	cellBackground struct {
+		color color.Color
+	}
+	cellBorders struct {
+		sides BorderSide
+	}
+	cellBorderPen struct {
+		usePen pen
+	}
+	styledCell struct {
+		draw    DrawStruct
+		options []CellOption
+	}
+	styledRow struct {
+		row     TableRow
+		options []CellOption
+	}
+	tableZebra struct {
+		color color.Color
+	}
+	tableHideInnerVertical struct{}
+	tableOptions           struct {
+		zebra             color.Color
+		hideInnerVertical bool
+		noHeader          bool
+		headerStyle       []CellOption
+		headerGroups      [][]HeaderGroup
+		footer            []TableRow
+		footerStyle       []CellOption
+		groupBy           []tableGroupBy
+		groupStyle        []CellOption
+	}
+	cellStyle struct {
+		background color.Color
+		sides      BorderSide
+		usePen     pen
+		text       []TextOption
+	}
+)
+
+const (
+	BorderLeft BorderSide = 1 << iota
+	BorderTop
+	BorderRight
+	BorderBottom
+
+	BorderNone BorderSide = 0
+	BorderAll             = BorderLeft | BorderTop | BorderRight | BorderBottom
+)
+
+// Cell applies the options to the single cell of the table, they take precedence over the row and column options
+func Cell(d DrawStruct, options ...CellOption) DrawStruct {
+	return styledCell{
+		draw:    d,
+		options: options,
+	}
+}
+
+func (c styledCell) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	return c.draw.WriteTo(canvas, rect)
+}
+
+func (c styledCell) measureContent(m measurer) (minWidth, maxWidth int) {
+	return measureStruct(m, c.draw)
+}
+
+func (c styledCell) writeGrid(g *TextCanvas, rect image.Rectangle) image.Point {
+	return g.writeStruct(c.draw, rect)
+}
+
+// StyleRow applies the options to all the cells of the row, they take precedence over the column options
+func StyleRow(row TableRow, options ...CellOption) TableRow {
+	return styledRow{
+		row:     row,
+		options: options,
+	}
+}
+
+func (r styledRow) getColumnByNum(num int) DrawStruct {
+	return r.row.getColumnByNum(num)
+}
+
+func (r styledRow) rowOptions() []CellOption {
+	if s, ok := r.row.(RowStyler); ok {
+		return append(s.rowOptions(), r.options...)
+	}
+	return r.options
+}
+
+// OptionBackground fills the cell (or the box) with the color
+func OptionBackground(c color.Color) BackgroundOption {
+	return cellBackground{color: c}
+}
+
+// OptionBorders sets the sides of the cell on which the border is drawn, e.g.
+//
+//	OptionBorders(BorderTop | BorderBottom)
+func OptionBorders(sides BorderSide) CellOption {
+	return cellBorders{sides: sides}
+}
+
+// OptionBorderPen sets the color and thickness of the cell border, use NewPen to make it
+func OptionBorderPen(usePen pen) CellOption {
+	return cellBorderPen{usePen: usePen}
+}
+
+// OptionZebra fills every second row of the table body with the color
+func OptionZebra(c color.Color) TableOption {
+	return tableZebra{color: c}
+}
+
+// OptionHideInnerVertical hides the vertical lines between the columns, only the outer border is drawn
+func OptionHideInnerVertical() TableOption {
+	return tableHideInnerVertical{}
+}
+
+func (_ cellBackground) cellOptInt() int {
+	return 0
+}
+
+func (_ cellBorders) cellOptInt() int {
+	return 0
+}
+
+func (_ cellBorderPen) cellOptInt() int {
+	return 0
+}
+
+func (_ textFont) cellOptInt() int {
+	return 0
+}
+
+func (_ textAlignment) cellOptInt() int {
+	return 0
+}
+
+func (_ textCentered) cellOptInt() int {
+	return 0
+}
+
+func (_ tableZebra) tableOptInt() int {
+	return 0
+}
+
+func (_ tableHideInnerVertical) tableOptInt() int {
+	return 0
+}
+
+func (t table) WithOptions(options ...TableOption) DrawTable {
+	for _, opt := range options {
+		switch v := opt.(type) {
+		case tableZebra:
+			t.options.zebra = v.color
+		case tableHideInnerVertical:
+			t.options.hideInnerVertical = true
+		case tableNoHeader:
+			t.options.noHeader = true
+		case tableHeaderStyle:
+			t.options.headerStyle = append(append([]CellOption{}, t.options.headerStyle...), v.options...)
+		case tableHeaderGroups:
+			t.options.headerGroups = append(append([][]HeaderGroup{}, t.options.headerGroups...), v.groups)
+		case tableFooter:
+			t.options.footer = append(append([]TableRow{}, t.options.footer...), v.rows...)
+		case tableFooterStyle:
+			t.options.footerStyle = append(append([]CellOption{}, t.options.footerStyle...), v.options...)
+		case tableGroupBy:
+			t.options.groupBy = append(append([]tableGroupBy{}, t.options.groupBy...), v)
+		case tableGroupStyle:
+			t.options.groupStyle = append(append([]CellOption{}, t.options.groupStyle...), v.options...)
+		}
+	}
+	return t
+}
+
+// rowOptions returns the options of the body row with the index, zebra striping goes first
+// so that the options of the row itself can override it
+func (t table) rowOptions(index int, row TableRow) []CellOption {
+	var options []CellOption
+	if t.options.zebra != nil && index%2 == 1 {
+		options = append(options, OptionBackground(t.options.zebra))
+	}
+	return append(options, rowStyle(row)...)
+}
+
+func rowStyle(row TableRow) []CellOption {
+	if s, ok := row.(RowStyler); ok {
+		return s.rowOptions()
+	}
+	return nil
+}
+
+// cellStyle resolves the look of the cell, the options of the cell override the options of the row
+func (l *tableLayout) cellStyle(cell tableCell, rowOptions []CellOption) cellStyle {
+	var (
+		column = l.table.columns[cell.col]
+		style  = cellStyle{
+			sides:  BorderAll,
+			usePen: column.getPen(),
+		}
+	)
+	if l.table.options.hideInnerVertical {
+		if cell.col > 0 {
+			style.sides &^= BorderLeft
+		}
+		if cell.col+cell.span < len(l.table.columns) {
+			style.sides &^= BorderRight
+		}
+	}
+	for _, options := range [][]CellOption{rowOptions, cell.options} {
+		var text []TextOption
+		for _, opt := range options {
+			switch v := opt.(type) {
+			case cellBackground:
+				style.background = v.color
+			case cellBorders:
+				style.sides = v.sides
+			case cellBorderPen:
+				style.usePen = v.usePen
+			case TextOption:
+				text = append(text, v)
+			}
+		}
+		// the later options take precedence
+		style.text = mergeTextOptions(text, style.text)
+	}
+	if cell.span == 1 && cell.col < len(l.decimals) && l.decimals[cell.col].aligned {
+		style.text = append(style.text, decimalTab{fraction: l.decimals[cell.col].fraction})
+	}
+	return style
+}
+
+// mergeTextOptions returns the primary options supplemented by the fallback options of the types
+// which are not present among the primary ones, later primary options take precedence over earlier ones
+func mergeTextOptions(primary, fallback []TextOption) []TextOption {
+	var (
+		merged = make([]TextOption, 0, len(primary)+len(fallback))
+		seen   = make(map[reflect.Type]struct{})
+	)
+	for _, options := range [][]TextOption{primary, fallback} {
+		for i := len(options) - 1; i >= 0; i-- {
+			rt := reflect.TypeOf(options[i])
+			if _, ok := seen[rt]; ok {
+				continue
+			}
+			seen[rt] = struct{}{}
+			merged = append(merged, options[i])
+		}
+	}
+	return merged
+}
@@ -1,9 +1,7 @@
 package receipt
 
 import (
-	"github.com/golang/freetype"
 	"github.com/golang/freetype/truetype"
-	"golang.org/x/image/font/gofont/goregular"
 	"image"
 	"reflect"
 )
@@ -17,6 +15,7 @@ type (
 	TextOption interface {
 		textOptInt() int
 		tableColumnOptInt() int
+		cellOptInt() int
 	}
 	textAlignment struct {
 		alignment Alignment
@@ -77,7 +76,7 @@ func OptionFont(font *truetype.Font, fontSize float64, usePen pen) TextOption {
 }
 
 // OptionAlignment lets you set horizontal alignment
-//  AlignLeft, AlignRight, AlignCenter
+//  AlignLeft, AlignRight, AlignCenter, AlignDecimal
 func OptionAlignment(a Alignment) TextOption {
 	return textAlignment{
 		alignment: a,
@@ -118,52 +117,83 @@ type (
 		text    string
 		options []TextOption
 	}
-)
-
-func getDefaultFont() *truetype.Font {
-	fontFace, err := freetype.ParseFont(goregular.TTF)
-	if err != nil {
-		panic(err)
+	textStyle struct {
+		font      *truetype.Font
+		fontSize  float64
+		usePen    pen
+		alignment cellAlignment
+		separator rune
+		fraction  int
+		tab       Measure
 	}
-	return fontFace
-}
+)
 
-func (t text) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+func (t text) style() textStyle {
 	var (
-		font      *truetype.Font
-		fontSize  float64 = defaultFontSize
-		usePen            = defaultPen
-		alignment         = cellAlignment{
-			hAlign:    AlignLeft,
-			vCentered: false,
+		style = textStyle{
+			fontSize: defaultFontSize,
+			usePen:   defaultPen,
+			alignment: cellAlignment{
+				hAlign:    AlignLeft,
+				vCentered: false,
+			},
+			separator: defaultDecimalSeparator,
 		}
+		customPen = false
 	)
-	var customPen = false
 	for _, opt := range t.options {
 		switch v := opt.(type) {
 		case textFont:
-			font = v.font
+			style.font = v.font
 			if !customPen {
-				usePen = v.usePen
+				style.usePen = v.usePen
 			}
-			fontSize = v.fontSize
+			style.fontSize = v.fontSize
 		case textAlignment:
-			alignment.hAlign = v.alignment
+			style.alignment.hAlign = v.alignment
 		case textCentered:
-			alignment.vCentered = true
+			style.alignment.vCentered = true
 		case pen:
 			customPen = true
-			usePen = v
+			style.usePen = v
+		case textDecimalSeparator:
+			style.separator = v.separator
+		case decimalTab:
+			// the tab of the table column takes the place of the tab of the text
+			style.fraction = v.fraction
+			style.tab = nil
+		case textDecimalTab:
+			style.tab = v.width
 		}
 	}
-	if font == nil {
-		font = getDefaultFont()
+	return style
+}
+
+func (t text) WriteTo(canvas Canvas, rect image.Rectangle) image.Point {
+	style := t.style()
+	if style.font == nil {
+		style.font = getDefaultFont()
 	}
+	drawer := canvas.fontDrawer(canvas.img, style)
+	rect.Max.X -= style.decimalShift(canvas, t.text, func(s string) int {
+		return drawer.MeasureString(s).Ceil()
+	})
 	lastY := fillTextIntoRect(
-		makeFontDrawer(canvas.img, font, usePen.color, fontSize),
+		drawer,
 		t.text,
 		rect,
-		alignment,
+		style.alignment,
 	)
 	return image.Point{X: rect.Max.X, Y: lastY}
 }
+
+func (t text) measureContent(m measurer) (minWidth, maxWidth int) {
+	style := t.style()
+	minWidth, maxWidth = m.measureText(t.text, style)
+	// the text lined up on the decimal tab takes the room up to the tab
+	shift := style.decimalShift(m, t.text, func(s string) int {
+		_, w := m.measureText(s, style)
+		return w
+	})
+	return minWidth + shift, maxWidth + shift
+}
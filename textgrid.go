@@ -0,0 +1,413 @@
+package receipt
+
+import (
+	"image"
+	"math"
+	"strings"
+)
+
+type (
+	// TextCanvas is the character-cell counterpart of Canvas. It is intended for impact and
+	// thermal printers which print fixed-width text only: the same document tree of Lines,
+	// Cols, Table and Text is laid out into columns and lines instead of pixels
+	TextCanvas struct {
+		columns int
+		width   Measure
+		borders BorderStyle
+		cells   map[image.Point]rune
+		edges   map[image.Point]edgeMask
+		lines   int
+		point   image.Point
+		layers  layers
+		// dpi converts the measures in pixels to millimeters
+		dpi float64
+	}
+	// BorderStyle selects the characters used to draw table borders on TextCanvas:
+	//  BordersASCII, BordersBox
+	BorderStyle int
+
+	gridWriter interface {
+		writeGrid(*TextCanvas, image.Rectangle) image.Point
+	}
+	edgeMask uint8
+)
+
+const (
+	BordersASCII BorderStyle = iota
+	BordersBox
+)
+
+const (
+	edgeUp edgeMask = 1 << iota
+	edgeDown
+	edgeLeft
+	edgeRight
+)
+
+// the character cell of most printer fonts is about twice as high as it is wide
+const gridCellAspect = 2.0
+
+var boxDrawingChars = map[edgeMask]rune{
+	edgeLeft:                                 '─',
+	edgeRight:                                '─',
+	edgeLeft | edgeRight:                     '─',
+	edgeUp:                                   '│',
+	edgeDown:                                 '│',
+	edgeUp | edgeDown:                        '│',
+	edgeDown | edgeRight:                     '┌',
+	edgeDown | edgeLeft:                      '┐',
+	edgeUp | edgeRight:                       '└',
+	edgeUp | edgeLeft:                        '┘',
+	edgeUp | edgeDown | edgeRight:            '├',
+	edgeUp | edgeDown | edgeLeft:             '┤',
+	edgeDown | edgeLeft | edgeRight:          '┬',
+	edgeUp | edgeLeft | edgeRight:            '┴',
+	edgeUp | edgeDown | edgeLeft | edgeRight: '┼',
+}
+
+// NewTextCanvas creates a character grid with the specified number of columns (32, 42, 48 are
+// typical for receipt printers). The width is the printable width of the paper, it is used to
+// map Measure values (paddings, fillers) to a number of columns and lines
+func NewTextCanvas(columns int, width Measure) *TextCanvas {
+	return &TextCanvas{
+		columns: columns,
+		width:   width,
+		borders: BordersASCII,
+		cells:   make(map[image.Point]rune),
+		edges:   make(map[image.Point]edgeMask),
+		dpi:     dpi,
+	}
+}
+
+// SetDPI sets the resolution the measures in pixels are mapped to the columns at, the default one is used otherwise
+func (c *TextCanvas) SetDPI(dpi float64) {
+	c.dpi = dpi
+}
+
+// DPI returns the resolution of the canvas
+func (c *TextCanvas) DPI() float64 {
+	return c.dpi
+}
+
+// SetBorders allows you to choose between ASCII and box-drawing characters for table borders
+func (c *TextCanvas) SetBorders(b BorderStyle) {
+	c.borders = b
+}
+
+// Write lays out the block of objects, starting from the line
+// at which the previous block of objects was completed
+func (c *TextCanvas) Write(d DrawStruct) image.Point {
+	c.point = c.writeStruct(d, image.Rect(0, c.point.Y, c.columns, c.point.Y))
+	c.growTo(c.point.Y)
+	return c.point
+}
+
+// String returns the printable text, lines are separated by '\n' and have no trailing spaces
+func (c *TextCanvas) String() string {
+	var sb strings.Builder
+	for y := 0; y < c.lines; y++ {
+		line := make([]rune, c.columns)
+		for x := range line {
+			line[x] = c.charAt(image.Point{X: x, Y: y})
+		}
+		sb.WriteString(strings.TrimRight(string(line), " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func (c *TextCanvas) writeStruct(d DrawStruct, rect image.Rectangle) image.Point {
+	if g, ok := d.(gridWriter); ok {
+		return g.writeGrid(c, rect)
+	}
+	// objects which have no text representation take up no space
+	return rect.Min
+}
+
+// scratch returns an empty canvas of the same size, it is used to measure objects
+func (c *TextCanvas) scratch() *TextCanvas {
+	s := NewTextCanvas(c.columns, c.width)
+	s.borders = c.borders
+	s.dpi = c.dpi
+	return s
+}
+
+func (c *TextCanvas) columnWidth() float64 {
+	return c.width.toMillimeter(c.dpi) / float64(c.columns)
+}
+
+// toColumns maps the measure to the number of whole columns it covers
+func (c *TextCanvas) toColumns(m Measure) int {
+	return int(math.Floor(m.toMillimeter(c.dpi)/c.columnWidth() + 1e-9))
+}
+
+// toLines maps the measure to the number of whole lines it covers
+func (c *TextCanvas) toLines(m Measure) int {
+	return int(math.Floor(m.toMillimeter(c.dpi)/(c.columnWidth()*gridCellAspect) + 1e-9))
+}
+
+func (c *TextCanvas) growTo(y int) {
+	if y > c.lines {
+		c.lines = y
+	}
+}
+
+func (c *TextCanvas) inside(p image.Point) bool {
+	return p.X >= 0 && p.X < c.columns && p.Y >= 0
+}
+
+func (c *TextCanvas) putString(x, y int, s string) {
+	for _, r := range s {
+		p := image.Point{X: x, Y: y}
+		if c.inside(p) {
+			c.cells[p] = r
+			c.growTo(y + 1)
+		}
+		x++
+	}
+}
+
+func (c *TextCanvas) putEdge(p image.Point, e edgeMask) {
+	if c.inside(p) {
+		c.edges[p] |= e
+		c.growTo(p.Y + 1)
+	}
+}
+
+func (c *TextCanvas) hLine(y, x0, x1 int) {
+	for x := x0; x <= x1; x++ {
+		var e edgeMask
+		if x > x0 {
+			e |= edgeLeft
+		}
+		if x < x1 {
+			e |= edgeRight
+		}
+		c.putEdge(image.Point{X: x, Y: y}, e)
+	}
+}
+
+func (c *TextCanvas) vLine(x, y0, y1 int) {
+	for y := y0; y <= y1; y++ {
+		var e edgeMask
+		if y > y0 {
+			e |= edgeUp
+		}
+		if y < y1 {
+			e |= edgeDown
+		}
+		c.putEdge(image.Point{X: x, Y: y}, e)
+	}
+}
+
+func (c *TextCanvas) charAt(p image.Point) rune {
+	if r, ok := c.cells[p]; ok {
+		return r
+	}
+	e := c.edges[p]
+	if e == 0 {
+		return ' '
+	}
+	if c.borders == BordersBox {
+		return boxDrawingChars[e]
+	}
+	switch {
+	case e&(edgeUp|edgeDown) == 0:
+		return '-'
+	case e&(edgeLeft|edgeRight) == 0:
+		return '|'
+	default:
+		return '+'
+	}
+}
+
+// splitTextToWidth wraps the text by words, words which are longer than the width are broken
+func splitTextToWidth(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+	var (
+		result []string
+		line   []rune
+	)
+	for _, word := range strings.Split(text, " ") {
+		w := []rune(word)
+		if len(line) > 0 && len(line)+1+len(w) <= width {
+			line = append(append(line, ' '), w...)
+			continue
+		}
+		if len(line) > 0 {
+			result = append(result, string(line))
+		}
+		for len(w) > width {
+			result = append(result, string(w[:width]))
+			w = w[width:]
+		}
+		line = w
+	}
+	return append(result, string(line))
+}
+
+func (t text) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	var (
+		style = t.style()
+		lines []string
+		y     = rect.Min.Y
+	)
+	rect.Max.X -= style.decimalShift(c, t.text, func(s string) int {
+		return len([]rune(s))
+	})
+	lines = splitTextToWidth(t.text, rect.Dx())
+	if style.alignment.vCentered && rect.Dy() > len(lines) {
+		y += (rect.Dy() - len(lines)) / 2
+	}
+	for _, line := range lines {
+		x := rect.Min.X
+		switch style.alignment.hAlign {
+		case AlignRight, AlignDecimal:
+			x = rect.Max.X - len([]rune(line))
+		case AlignCenter:
+			x += (rect.Dx() - len([]rune(line))) / 2
+		}
+		c.putString(x, y, line)
+		y++
+	}
+	return image.Point{X: rect.Max.X, Y: y}
+}
+
+func (l lines) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	rect.Max.Y = rect.Min.Y
+	for _, d := range l.lines {
+		point := c.writeStruct(d, rect)
+		rect.Min.Y = point.Y
+		rect.Max.Y = point.Y
+	}
+	return image.Point{X: rect.Max.X, Y: rect.Max.Y}
+}
+
+func (cl cols) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	var (
+		bottom  = rect.Max.Y
+		left    = rect.Min.X
+		columns = make([]image.Rectangle, len(cl.cols))
+	)
+	for i, w := range cl.widths(c, rect.Dx()) {
+		columns[i] = image.Rect(left, rect.Min.Y, left+w, rect.Max.Y)
+		left += w
+		if fillsRow(cl.cols[i]) {
+			continue
+		}
+		point := c.writeStruct(cl.cols[i], columns[i])
+		if bottom < point.Y {
+			bottom = point.Y
+		}
+	}
+	for i, d := range cl.cols {
+		if fillsRow(d) {
+			columns[i].Max.Y = bottom
+			c.writeStruct(d, columns[i])
+		}
+	}
+	return image.Point{X: rect.Max.X, Y: bottom}
+}
+
+func (p padding) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	var (
+		l = c.toColumns(p.paddingLeft)
+		t = c.toLines(p.paddingTop)
+		r = c.toColumns(p.paddingRight)
+		b = c.toLines(p.paddingBottom)
+	)
+	inner := image.Rect(rect.Min.X+l, rect.Min.Y+t, rect.Max.X-r, rect.Max.Y-b)
+	if inner.Max.Y < inner.Min.Y {
+		inner.Max.Y = inner.Min.Y
+	}
+	result := c.writeStruct(p.content, inner)
+	return image.Point{X: rect.Min.X, Y: result.Y + b}
+}
+
+func (f fixedFiller) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	return image.Point{
+		X: rect.Min.X + c.toColumns(f.x),
+		Y: rect.Min.Y + c.toLines(f.y),
+	}
+}
+
+func (e empty) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	return rect.Min
+}
+
+func (cs colSpan) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	return c.writeStruct(cs.draw, rect)
+}
+
+func (r rowSpan) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	return c.writeStruct(r.draw, rect)
+}
+
+// gridColumnBounds returns the positions of the vertical borders of the table columns
+func (t table) gridColumnBounds(c *TextCanvas, rect image.Rectangle) []int {
+	var (
+		bounds = make([]int, 0, len(t.columns)+1)
+		left   = rect.Min.X
+	)
+	bounds = append(bounds, left)
+	for _, w := range t.columnWidths(c, rect.Dx()-1, c.borderWidth()) {
+		left += w
+		bounds = append(bounds, left)
+	}
+	return bounds
+}
+
+type gridTable struct {
+	canvas *TextCanvas
+}
+
+func (g gridTable) writeCell(column TableColumn, cell tableCell, rect image.Rectangle, style cellStyle) int {
+	var (
+		draw, padFunc = column.extractDrawStruct(cell.draw, style.text...)
+		cellRect      = image.Rect(rect.Min.X+1, rect.Min.Y+1, rect.Max.X, rect.Max.Y)
+	)
+	if cellRect.Max.Y < cellRect.Min.Y {
+		cellRect.Max.Y = cellRect.Min.Y
+	}
+	if _, ok := draw.(table); ok {
+		// the nested table draws its outer border over the border of the cell, so its bottom line is the bottom of the row
+		cellRect = image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X+1, rect.Max.Y)
+		return g.canvas.writeStruct(draw, cellRect).Y - 1
+	}
+	return g.canvas.writeStruct(padFunc(draw), cellRect).Y
+}
+
+func (g gridTable) measureCell(column TableColumn, cell tableCell, rect image.Rectangle, style cellStyle) int {
+	return gridTable{canvas: g.canvas.scratch()}.writeCell(column, cell, rect, style)
+}
+
+func (g gridTable) fillCell(image.Rectangle, cellStyle) {
+	// there are no backgrounds for text
+}
+
+func (g gridTable) drawBorder(rect image.Rectangle, style cellStyle) {
+	if style.sides&BorderTop != 0 {
+		g.canvas.hLine(rect.Min.Y, rect.Min.X, rect.Max.X)
+	}
+	if style.sides&BorderBottom != 0 {
+		g.canvas.hLine(rect.Max.Y, rect.Min.X, rect.Max.X)
+	}
+	if style.sides&BorderLeft != 0 {
+		g.canvas.vLine(rect.Min.X, rect.Min.Y, rect.Max.Y)
+	}
+	if style.sides&BorderRight != 0 {
+		g.canvas.vLine(rect.Max.X, rect.Min.Y, rect.Max.Y)
+	}
+}
+
+func (t table) writeGrid(c *TextCanvas, rect image.Rectangle) image.Point {
+	layout := newTableLayout(t, t.gridColumnBounds(c, rect))
+	layout.decimals = t.decimalColumns(c)
+	bottom := t.writeRows(gridTable{canvas: c}, layout, t.layoutRows(), rect.Min.Y, 2, 2)
+	return image.Point{
+		X: rect.Min.X,
+		Y: bottom + 1,
+	}
+}